@@ -0,0 +1,587 @@
+// Command dler-prompt exposes the prompts to shell scripts. The prompt is rendered on
+// the terminal (/dev/tty), the answer is printed to stdout and the exit code tells
+// cancellations, timeouts and going back apart:
+//
+//	branch=$(git branch --format='%(refname:short)' | dler-prompt select --header "Branch?")
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reliverse/dler/packages/prompt/prompts"
+	"golang.org/x/term"
+)
+
+const (
+	exitOK        = 0
+	exitError     = 1
+	exitUsage     = 2
+	exitBack      = 3
+	exitTimeout   = 124
+	exitCancelled = 130
+)
+
+const usage = `Usage: dler-prompt <command> [flags]
+
+Commands:
+  select             choose one item, prints its value
+  multiselect        choose several items, prints one value per line
+  group-multiselect  choose items from groups, prints one value per line
+  confirm            yes/no question, prints "true" or "false"
+  input              free text, prints the entered text
+  serve              answer JSON-RPC 2.0 requests on stdin/stdout, one message per line
+
+Items are given with repeated --option VALUE[=LABEL] flags or on stdin, either as a
+JSON array (of strings or {"value","label","hint","disabled"} objects) or one per line.
+Objects with "kind" set to "separator" or "label" divide select and multiselect lists
+into sections. Items of select may open submenus with a "children" array.
+
+Exit codes: 0 answered, 1 error, 2 usage, 3 went back (--back-key pressed),
+124 timed out, 130 cancelled.
+Run "dler-prompt <command> -h" for the flags of a command.
+`
+
+// promptError is a failed prompt mapped to an exit code.
+type promptError struct {
+	code int
+	msg  string
+}
+
+func (e *promptError) Error() string {
+	return e.msg
+}
+
+// resultError converts the error of a prompt.
+func resultError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, prompts.ErrCancelled):
+		return &promptError{code: exitCancelled, msg: "cancelled"}
+	case errors.Is(err, prompts.ErrBack):
+		return &promptError{code: exitBack, msg: "went back"}
+	case errors.Is(err, prompts.ErrTimeout):
+		return &promptError{code: exitTimeout, msg: "timed out"}
+	}
+	var perr *prompts.PromptError
+	if errors.As(err, &perr) && (perr.Code == prompts.CodeInvalidInput || perr.Code == prompts.CodeInvalidInputJSON) {
+		return &promptError{code: exitUsage, msg: err.Error()}
+	}
+	return &promptError{code: exitError, msg: err.Error()}
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// commonFlags are shared by every command.
+type commonFlags struct {
+	header  string
+	footer  string
+	backKey string
+	timeout int
+}
+
+func (c *commonFlags) register(fs *flag.FlagSet, defaultHeader string) {
+	fs.StringVar(&c.header, "header", defaultHeader, "text shown above the prompt")
+	fs.StringVar(&c.footer, "footer", "", "text shown below the prompt")
+	fs.StringVar(&c.backKey, "back-key", "", `key that leaves the prompt with exit 3, e.g. "shift+tab"`)
+	fs.IntVar(&c.timeout, "timeout", 0, "seconds until the prompt resolves with its default (exit 124 without one)")
+}
+
+// options returns the prompt options for the shared flags.
+func (c *commonFlags) options() []prompts.Option {
+	return []prompts.Option{
+		prompts.WithHeader(c.header),
+		prompts.WithFooter(c.footer),
+		prompts.WithBackKey(c.backKey),
+		prompts.WithTimeout(time.Duration(c.timeout)*time.Second, false),
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(exitUsage)
+	}
+
+	command, args := os.Args[1], os.Args[2:]
+	if command == "serve" {
+		if err := runServe(); err != nil {
+			fmt.Fprintf(os.Stderr, "dler-prompt: %s\n", err)
+			os.Exit(exitError)
+		}
+		return
+	}
+
+	var run func(args []string) (string, error)
+	switch command {
+	case "select":
+		run = runSelect
+	case "multiselect":
+		run = runMultiselect
+	case "group-multiselect":
+		run = runGroupMultiselect
+	case "confirm":
+		run = runConfirm
+	case "input":
+		run = runInput
+	case "-h", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "dler-prompt: unknown command %q\n\n%s", command, usage)
+		os.Exit(exitUsage)
+	}
+
+	output, err := run(args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		var perr *promptError
+		if errors.As(err, &perr) {
+			if perr.code == exitError || perr.code == exitUsage {
+				fmt.Fprintf(os.Stderr, "dler-prompt: %s\n", perr.msg)
+			}
+			os.Exit(perr.code)
+		}
+		fmt.Fprintf(os.Stderr, "dler-prompt: %s\n", err)
+		os.Exit(exitError)
+	}
+	fmt.Fprintln(os.Stdout, output)
+}
+
+// parseFlags parses args, mapping flag errors to the usage exit code.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &promptError{code: exitUsage, msg: err.Error()}
+	}
+	if fs.NArg() > 0 {
+		return &promptError{code: exitUsage, msg: fmt.Sprintf("unexpected argument %q", fs.Arg(0))}
+	}
+	return nil
+}
+
+// onTerminal runs fn once a terminal for the prompt is available. Prompts render on
+// /dev/tty by default, which keeps stdout free for the answer; without it the prompt
+// falls back to stderr when that is a terminal.
+func onTerminal(fn func() error) error {
+	if tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0); err == nil {
+		tty.Close()
+		return resultError(fn())
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stderr.Fd())) {
+		return &promptError{code: exitError, msg: "no terminal available to show the prompt"}
+	}
+	prompts.SetTerminal(os.Stdin, os.Stderr)
+	defer prompts.SetTerminal(nil, nil)
+	return resultError(fn())
+}
+
+// runServe speaks JSON-RPC over stdin/stdout, so the prompts must render on /dev/tty.
+func runServe() error {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return errors.New("serve needs /dev/tty to show prompts")
+	}
+	tty.Close()
+	return prompts.ServeRPC(os.Stdin, os.Stdout)
+}
+
+// readItems builds the item list from --option flags, or from stdin when none were given.
+func readItems(options []string) ([]prompts.ListItem, error) {
+	if len(options) > 0 {
+		items := make([]prompts.ListItem, 0, len(options))
+		for _, option := range options {
+			value, label := option, option
+			if i := strings.Index(option, "="); i >= 0 {
+				value, label = option[:i], option[i+1:]
+			}
+			items = append(items, prompts.ListItem{Value: value, Label: label})
+		}
+		return items, nil
+	}
+
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, &promptError{code: exitUsage, msg: "no items: pass --option flags or pipe items on stdin"}
+	}
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("reading items: %w", err)
+	}
+	return parseItems(data)
+}
+
+// defaultLabels labels items without a label, and the items of their submenus, with their value.
+func defaultLabels(items []prompts.ListItem) {
+	for i := range items {
+		if items[i].Label == "" {
+			items[i].Label = items[i].Value
+		}
+		defaultLabels(items[i].Children)
+	}
+}
+
+// parseItems accepts a JSON array of strings or items, or newline-separated values.
+func parseItems(data []byte) ([]prompts.ListItem, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var items []prompts.ListItem
+		if err := json.Unmarshal([]byte(trimmed), &items); err == nil {
+			defaultLabels(items)
+			return items, nil
+		}
+		var values []string
+		if err := json.Unmarshal([]byte(trimmed), &values); err != nil {
+			return nil, &promptError{code: exitUsage, msg: fmt.Sprintf("invalid JSON items: %s", err)}
+		}
+		items = make([]prompts.ListItem, 0, len(values))
+		for _, value := range values {
+			items = append(items, prompts.ListItem{Value: value, Label: value})
+		}
+		return items, nil
+	}
+
+	var items []prompts.ListItem
+	scanner := bufio.NewScanner(strings.NewReader(trimmed))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		items = append(items, prompts.ListItem{Value: line, Label: line})
+	}
+	return items, nil
+}
+
+// hasChildren reports whether any of items opens a submenu.
+func hasChildren(items []prompts.ListItem) bool {
+	for _, item := range items {
+		if len(item.Children) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// valuesAt maps the indices of a multiselect result back to item values, in list order.
+func valuesAt(indices []int, value func(i int) (string, bool)) (string, error) {
+	values := make([]string, 0, len(indices))
+	for _, i := range indices {
+		v, ok := value(i)
+		if !ok {
+			return "", fmt.Errorf("index %d out of range", i)
+		}
+		values = append(values, v)
+	}
+	return strings.Join(values, "\n"), nil
+}
+
+func runSelect(args []string) (string, error) {
+	fs := flag.NewFlagSet("select", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs, "Select an item:")
+	var options stringList
+	fs.Var(&options, "option", "item as VALUE or VALUE=LABEL (repeatable)")
+	perPage := fs.Int("per-page", 5, "number of visible items")
+	defaultValue := fs.String("default", "", "value used on timeout and preselected")
+	initialValue := fs.String("initial", "", "value the cursor starts on")
+	noAutocomplete := fs.Bool("no-autocomplete", false, "disable type-to-search")
+	other := fs.String("other", "", "label of an extra entry for typing a value that is not listed")
+	printPath := fs.Bool("path", false, "print the values from the top-level item down to the answer, one per line")
+	if err := parseFlags(fs, args); err != nil {
+		return "", err
+	}
+
+	items, err := readItems(options)
+	if err != nil {
+		return "", err
+	}
+	if len(items) == 0 {
+		return "", &promptError{code: exitUsage, msg: "no items to select from"}
+	}
+
+	if *printPath || hasChildren(items) {
+		// Nested items are answered with a path; --other only applies to flat lists
+		if *other != "" {
+			return "", &promptError{code: exitUsage, msg: "--other cannot be combined with nested items or --path"}
+		}
+		var path []string
+		err = onTerminal(func() (err error) {
+			path, err = prompts.SelectPath(items, append(common.options(),
+				prompts.WithPerPage(*perPage),
+				prompts.WithAutocomplete(!*noAutocomplete),
+				prompts.WithDefault(*defaultValue),
+				prompts.WithInitial(*initialValue),
+			)...)
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(path) == 0 {
+			return "", errors.New("empty selection path")
+		}
+		if *printPath {
+			return strings.Join(path, "\n"), nil
+		}
+		return path[len(path)-1], nil
+	}
+
+	var i int
+	var custom string
+	err = onTerminal(func() (err error) {
+		i, custom, err = prompts.SelectOther(items, *other, append(common.options(),
+			prompts.WithPerPage(*perPage),
+			prompts.WithAutocomplete(!*noAutocomplete),
+			prompts.WithDefault(*defaultValue),
+			prompts.WithInitial(*initialValue),
+		)...)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	if custom != "" {
+		return custom, nil
+	}
+	if i < 0 || i >= len(items) {
+		return "", fmt.Errorf("invalid selection index %d", i)
+	}
+	return items[i].Value, nil
+}
+
+func runMultiselect(args []string) (string, error) {
+	fs := flag.NewFlagSet("multiselect", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs, "Select items:")
+	var options, selected stringList
+	fs.Var(&options, "option", "item as VALUE or VALUE=LABEL (repeatable)")
+	fs.Var(&selected, "selected", "preselected value, also used on timeout (repeatable)")
+	perPage := fs.Int("per-page", 5, "number of visible items")
+	noAutocomplete := fs.Bool("no-autocomplete", false, "disable type-to-search")
+	other := fs.String("other", "", "label of an extra entry for typing a value that is not listed")
+	if err := parseFlags(fs, args); err != nil {
+		return "", err
+	}
+
+	items, err := readItems(options)
+	if err != nil {
+		return "", err
+	}
+	if len(items) == 0 {
+		return "", &promptError{code: exitUsage, msg: "no items to select from"}
+	}
+	cursor := ""
+	if len(selected) > 0 {
+		cursor = selected[0]
+	}
+
+	var indices []int
+	var custom string
+	err = onTerminal(func() (err error) {
+		indices, custom, err = prompts.SelectManyOther(items, *other, append(common.options(),
+			prompts.WithPerPage(*perPage),
+			prompts.WithAutocomplete(!*noAutocomplete),
+			prompts.WithPreselected(selected...),
+			prompts.WithInitial(cursor),
+		)...)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	values, err := valuesAt(indices, func(i int) (string, bool) {
+		if i < 0 || i >= len(items) {
+			return "", false
+		}
+		return items[i].Value, true
+	})
+	if err != nil || custom == "" {
+		return values, err
+	}
+	if values == "" {
+		return custom, nil
+	}
+	return values + "\n" + custom, nil
+}
+
+func runGroupMultiselect(args []string) (string, error) {
+	fs := flag.NewFlagSet("group-multiselect", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs, "Select items:")
+	var selected stringList
+	fs.Var(&selected, "selected", "preselected value, also used on timeout (repeatable)")
+	perPage := fs.Int("per-page", 10, "number of visible items")
+	noAutocomplete := fs.Bool("no-autocomplete", false, "disable type-to-search")
+	selectableGroups := fs.Bool("selectable-groups", false, "allow toggling a whole group from its header")
+	groupSpacing := fs.Int("group-spacing", 0, "blank lines between groups")
+	if err := parseFlags(fs, args); err != nil {
+		return "", err
+	}
+
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", &promptError{code: exitUsage, msg: `pipe the groups on stdin as a JSON object: {"group": [items...]}`}
+	}
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("reading items: %w", err)
+	}
+	items, err := parseGroups(data)
+	if err != nil {
+		return "", err
+	}
+	cursor := ""
+	if len(selected) > 0 {
+		cursor = selected[0]
+	}
+
+	var indices []int
+	err = onTerminal(func() (err error) {
+		indices, err = prompts.SelectGrouped(items, append(common.options(),
+			prompts.WithPerPage(*perPage),
+			prompts.WithAutocomplete(!*noAutocomplete),
+			prompts.WithSelectableGroups(*selectableGroups),
+			prompts.WithPreselected(selected...),
+			prompts.WithInitial(cursor),
+			prompts.WithGroupSpacing(*groupSpacing),
+		)...)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return valuesAt(indices, func(i int) (string, bool) {
+		if i < 0 || i >= len(items) || items[i].IsGroupHeader {
+			return "", false
+		}
+		return items[i].Value, true
+	})
+}
+
+// parseGroups reads {"group": [items...]} (keys keep their order) into the flat list
+// with group headers that GroupMultiselect expects.
+func parseGroups(data []byte) ([]prompts.GroupListItem, error) {
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, &promptError{code: exitUsage, msg: `groups must be a JSON object: {"group": [items...]}`}
+	}
+	var items []prompts.GroupListItem
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, &promptError{code: exitUsage, msg: fmt.Sprintf("invalid groups: %s", err)}
+		}
+		group := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, &promptError{code: exitUsage, msg: fmt.Sprintf("invalid group %q: %s", group, err)}
+		}
+		groupItems, err := parseItems(raw)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, prompts.GroupListItem{Value: group, Label: group, IsGroupHeader: true, GroupName: group})
+		for _, item := range groupItems {
+			items = append(items, prompts.GroupListItem{Value: item.Value, Label: item.Label, Hint: item.Hint, Disabled: item.Disabled, GroupName: group})
+		}
+	}
+	if len(items) == 0 {
+		return nil, &promptError{code: exitUsage, msg: "no items to select from"}
+	}
+	return items, nil
+}
+
+func runConfirm(args []string) (string, error) {
+	fs := flag.NewFlagSet("confirm", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs, "")
+	message := fs.String("message", "Are you sure?", "the question")
+	defaultValue := fs.String("default", "", `"yes" or "no", used on Enter and timeout`)
+	if err := parseFlags(fs, args); err != nil {
+		return "", err
+	}
+
+	opts := common.options()
+	switch strings.ToLower(*defaultValue) {
+	case "":
+	case "y", "yes", "true":
+		opts = append(opts, prompts.WithDefaultAnswer(true))
+	case "n", "no", "false":
+		opts = append(opts, prompts.WithDefaultAnswer(false))
+	default:
+		return "", &promptError{code: exitUsage, msg: fmt.Sprintf("invalid --default %q, use yes or no", *defaultValue)}
+	}
+
+	var confirmed bool
+	err := onTerminal(func() (err error) {
+		confirmed, err = prompts.AskConfirm(*message, opts...)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatBool(confirmed), nil
+}
+
+func runInput(args []string) (string, error) {
+	fs := flag.NewFlagSet("input", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs, "")
+	message := fs.String("message", "", "the question")
+	defaultValue := fs.String("default", "", "value used when nothing is typed and on timeout")
+	initialValue := fs.String("initial", "", "prefilled, editable text")
+	password := fs.Bool("password", false, "mask the typed text")
+	required := fs.Bool("required", false, "do not accept an empty answer")
+	charLimit := fs.Int("char-limit", 0, "maximum number of characters")
+	if err := parseFlags(fs, args); err != nil {
+		return "", err
+	}
+
+	echoMode := prompts.EchoNormal
+	if *password {
+		echoMode = prompts.EchoPassword
+	}
+	promptText := *message
+	if promptText == "" {
+		promptText = common.header
+	}
+
+	var value string
+	err := onTerminal(func() (err error) {
+		value, err = prompts.AskInput(promptText, append(common.options(),
+			prompts.WithEchoMode(echoMode),
+			prompts.WithDefault(*defaultValue),
+			prompts.WithInitial(*initialValue),
+			prompts.WithRequired(*required),
+			prompts.WithCharLimit(*charLimit),
+		)...)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return value, nil
+}
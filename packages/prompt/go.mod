@@ -3,6 +3,7 @@ module github.com/reliverse/dler/packages/prompt
 go 1.18
 
 require (
+	github.com/charmbracelet/bubbles v0.8.0
 	github.com/charmbracelet/bubbletea v0.22.0
 	github.com/mritd/bubbles v0.0.0-20210825105013-cb7a572fb831
 	golang.org/x/term v0.0.0-20210927222741-03fcf44c2211
@@ -10,7 +11,6 @@ require (
 
 require (
 	github.com/atotto/clipboard v0.1.2 // indirect
-	github.com/charmbracelet/bubbles v0.8.0 // indirect
 	github.com/charmbracelet/lipgloss v0.1.2 // indirect
 	github.com/containerd/console v1.0.3 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
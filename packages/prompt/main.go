@@ -28,31 +28,224 @@ func FreeString(str *C.char) {
 }
 
 //export CreateSelection
-func CreateSelection(jsonData, headerText, footerText *C.char, perPage int, autocomplete bool, defaultValue, initialValue *C.char) *C.char {
-	result := prompts.Selection(str(jsonData), str(headerText), str(footerText), perPage, autocomplete, str(defaultValue), str(initialValue))
+func CreateSelection(jsonData, headerText, footerText *C.char, perPage int, autocomplete bool, defaultValue, initialValue, backKey *C.char, timeout int, resetTimeoutOnKey bool, otherLabel *C.char) *C.char {
+	result := prompts.Selection(str(jsonData), str(headerText), str(footerText), perPage, autocomplete, str(defaultValue), str(initialValue), str(backKey), timeout, resetTimeoutOnKey, str(otherLabel))
 	return ch(result)
 }
 
 //export CreatePrompt
-func CreatePrompt(prompText, echoMode, validateOkPrefix, validateErrPrefix, defaultValue, initialValue *C.char, required bool, charLimit int) *C.char {
-	result := prompts.Input(str(prompText), str(echoMode), str(validateOkPrefix), str(validateErrPrefix), str(defaultValue), str(initialValue), required, charLimit)
+func CreatePrompt(prompText, echoMode, validateOkPrefix, validateErrPrefix, defaultValue, initialValue *C.char, required bool, charLimit int, backKey *C.char, timeout int, resetTimeoutOnKey bool) *C.char {
+	result := prompts.Input(str(prompText), str(echoMode), str(validateOkPrefix), str(validateErrPrefix), str(defaultValue), str(initialValue), required, charLimit, str(backKey), timeout, resetTimeoutOnKey)
 	return ch(result)
 }
 
 //export CreateMultiselect
-func CreateMultiselect(jsonData, headerText, footerText *C.char, perPage int, autocomplete bool, preselectedValues, initialCursorValue *C.char) *C.char {
-	result := prompts.Multiselect(str(jsonData), str(headerText), str(footerText), perPage, autocomplete, str(preselectedValues), str(initialCursorValue))
+func CreateMultiselect(jsonData, headerText, footerText *C.char, perPage int, autocomplete bool, preselectedValues, initialCursorValue, backKey *C.char, timeout int, resetTimeoutOnKey bool, otherLabel *C.char) *C.char {
+	result := prompts.Multiselect(str(jsonData), str(headerText), str(footerText), perPage, autocomplete, str(preselectedValues), str(initialCursorValue), str(backKey), timeout, resetTimeoutOnKey, str(otherLabel))
 	return ch(result)
 }
 
 //export CreateConfirm
-func CreateConfirm(promptText, headerText, footerText *C.char, defaultValue, initialValue *C.char) *C.char {
-	result := prompts.Confirm(str(promptText), str(headerText), str(footerText), str(defaultValue), str(initialValue))
+func CreateConfirm(promptText, headerText, footerText *C.char, defaultValue, initialValue, backKey *C.char, timeout int, resetTimeoutOnKey bool) *C.char {
+	result := prompts.Confirm(str(promptText), str(headerText), str(footerText), str(defaultValue), str(initialValue), str(backKey), timeout, resetTimeoutOnKey)
 	return ch(result)
 }
 
 //export CreateGroupMultiselect
-func CreateGroupMultiselect(jsonData, headerText, footerText *C.char, perPage int, autocomplete, selectableGroups bool, preselectedValues, initialCursorValue *C.char, groupSpacing int) *C.char {
-	result := prompts.GroupMultiselect(str(jsonData), str(headerText), str(footerText), perPage, autocomplete, selectableGroups, str(preselectedValues), str(initialCursorValue), groupSpacing)
+func CreateGroupMultiselect(jsonData, headerText, footerText *C.char, perPage int, autocomplete, selectableGroups bool, preselectedValues, initialCursorValue *C.char, groupSpacing int, backKey *C.char, timeout int, resetTimeoutOnKey bool) *C.char {
+	result := prompts.GroupMultiselect(str(jsonData), str(headerText), str(footerText), perPage, autocomplete, selectableGroups, str(preselectedValues), str(initialCursorValue), groupSpacing, str(backKey), timeout, resetTimeoutOnKey)
 	return ch(result)
 }
+
+//export CreateGroupSelect
+func CreateGroupSelect(jsonData, headerText, footerText *C.char, perPage int, autocomplete bool, defaultValue, initialValue *C.char, groupSpacing int, backKey *C.char, timeout int, resetTimeoutOnKey bool) *C.char {
+	result := prompts.GroupSelect(str(jsonData), str(headerText), str(footerText), perPage, autocomplete, str(defaultValue), str(initialValue), groupSpacing, str(backKey), timeout, resetTimeoutOnKey)
+	return ch(result)
+}
+
+//export CreateForm
+func CreateForm(jsonData, headerText, footerText, backKey *C.char, timeout int, resetTimeoutOnKey bool) *C.char {
+	result := prompts.Form(str(jsonData), str(headerText), str(footerText), str(backKey), timeout, resetTimeoutOnKey)
+	return ch(result)
+}
+
+//export CreateDangerConfirm
+func CreateDangerConfirm(promptText, headerText, footerText, phrase, backKey *C.char, timeout int, resetTimeoutOnKey bool) *C.char {
+	result := prompts.DangerConfirm(str(promptText), str(headerText), str(footerText), str(phrase), str(backKey), timeout, resetTimeoutOnKey)
+	return ch(result)
+}
+
+//export CreateExpand
+func CreateExpand(jsonData, promptText, footerText, defaultKey, backKey *C.char, timeout int, resetTimeoutOnKey bool) *C.char {
+	result := prompts.Expand(str(jsonData), str(promptText), str(footerText), str(defaultKey), str(backKey), timeout, resetTimeoutOnKey)
+	return ch(result)
+}
+
+//export CreateTags
+func CreateTags(jsonData, headerText, footerText *C.char, perPage int, preselectedValues, backKey *C.char, timeout int, resetTimeoutOnKey bool) *C.char {
+	result := prompts.Tags(str(jsonData), str(headerText), str(footerText), perPage, str(preselectedValues), str(backKey), timeout, resetTimeoutOnKey)
+	return ch(result)
+}
+
+//export SpinnerStart
+func SpinnerStart(text *C.char) int {
+	return prompts.SpinnerStart(str(text))
+}
+
+//export SpinnerStartStyled
+func SpinnerStartStyled(text, framesJSON *C.char, delayMs int, color *C.char, hideElapsed bool) int {
+	return prompts.SpinnerStartStyled(str(text), str(framesJSON), delayMs, str(color), hideElapsed)
+}
+
+//export SpinnerUpdate
+func SpinnerUpdate(id int, text *C.char) bool {
+	return prompts.SpinnerUpdate(id, str(text))
+}
+
+//export SpinnerSucceed
+func SpinnerSucceed(id int, text *C.char) bool {
+	return prompts.SpinnerSucceed(id, str(text))
+}
+
+//export SpinnerFail
+func SpinnerFail(id int, text *C.char) bool {
+	return prompts.SpinnerFail(id, str(text))
+}
+
+//export SpinnerStop
+func SpinnerStop(id int) bool {
+	return prompts.SpinnerStop(id)
+}
+
+//export ProgressCreate
+func ProgressCreate(message *C.char, total int64) int {
+	return prompts.ProgressCreate(str(message), total)
+}
+
+//export ProgressSetTotal
+func ProgressSetTotal(id int, total int64) bool {
+	return prompts.ProgressSetTotal(id, total)
+}
+
+//export ProgressIncrement
+func ProgressIncrement(id int, delta int64) bool {
+	return prompts.ProgressIncrement(id, delta)
+}
+
+//export ProgressSetMessage
+func ProgressSetMessage(id int, message *C.char) bool {
+	return prompts.ProgressSetMessage(id, str(message))
+}
+
+//export ProgressFinish
+func ProgressFinish(id int, success bool, message *C.char) bool {
+	return prompts.ProgressFinish(id, success, str(message))
+}
+
+//export StartSelection
+func StartSelection(jsonData, headerText, footerText *C.char, perPage int, autocomplete bool, defaultValue, initialValue, backKey *C.char, timeout int, resetTimeoutOnKey bool, otherLabel *C.char) int {
+	return prompts.StartSelection(str(jsonData), str(headerText), str(footerText), perPage, autocomplete, str(defaultValue), str(initialValue), str(backKey), timeout, resetTimeoutOnKey, str(otherLabel))
+}
+
+//export StartPrompt
+func StartPrompt(prompText, echoMode, validateOkPrefix, validateErrPrefix, defaultValue, initialValue *C.char, required bool, charLimit int, backKey *C.char, timeout int, resetTimeoutOnKey bool) int {
+	return prompts.StartInput(str(prompText), str(echoMode), str(validateOkPrefix), str(validateErrPrefix), str(defaultValue), str(initialValue), required, charLimit, str(backKey), timeout, resetTimeoutOnKey)
+}
+
+//export StartMultiselect
+func StartMultiselect(jsonData, headerText, footerText *C.char, perPage int, autocomplete bool, preselectedValues, initialCursorValue, backKey *C.char, timeout int, resetTimeoutOnKey bool, otherLabel *C.char) int {
+	return prompts.StartMultiselect(str(jsonData), str(headerText), str(footerText), perPage, autocomplete, str(preselectedValues), str(initialCursorValue), str(backKey), timeout, resetTimeoutOnKey, str(otherLabel))
+}
+
+//export StartConfirm
+func StartConfirm(promptText, headerText, footerText *C.char, defaultValue, initialValue, backKey *C.char, timeout int, resetTimeoutOnKey bool) int {
+	return prompts.StartConfirm(str(promptText), str(headerText), str(footerText), str(defaultValue), str(initialValue), str(backKey), timeout, resetTimeoutOnKey)
+}
+
+//export StartGroupMultiselect
+func StartGroupMultiselect(jsonData, headerText, footerText *C.char, perPage int, autocomplete, selectableGroups bool, preselectedValues, initialCursorValue *C.char, groupSpacing int, backKey *C.char, timeout int, resetTimeoutOnKey bool) int {
+	return prompts.StartGroupMultiselect(str(jsonData), str(headerText), str(footerText), perPage, autocomplete, selectableGroups, str(preselectedValues), str(initialCursorValue), groupSpacing, str(backKey), timeout, resetTimeoutOnKey)
+}
+
+//export StartGroupSelect
+func StartGroupSelect(jsonData, headerText, footerText *C.char, perPage int, autocomplete bool, defaultValue, initialValue *C.char, groupSpacing int, backKey *C.char, timeout int, resetTimeoutOnKey bool) int {
+	return prompts.StartGroupSelect(str(jsonData), str(headerText), str(footerText), perPage, autocomplete, str(defaultValue), str(initialValue), groupSpacing, str(backKey), timeout, resetTimeoutOnKey)
+}
+
+//export StartForm
+func StartForm(jsonData, headerText, footerText, backKey *C.char, timeout int, resetTimeoutOnKey bool) int {
+	return prompts.StartForm(str(jsonData), str(headerText), str(footerText), str(backKey), timeout, resetTimeoutOnKey)
+}
+
+//export StartDangerConfirm
+func StartDangerConfirm(promptText, headerText, footerText, phrase, backKey *C.char, timeout int, resetTimeoutOnKey bool) int {
+	return prompts.StartDangerConfirm(str(promptText), str(headerText), str(footerText), str(phrase), str(backKey), timeout, resetTimeoutOnKey)
+}
+
+//export StartExpand
+func StartExpand(jsonData, promptText, footerText, defaultKey, backKey *C.char, timeout int, resetTimeoutOnKey bool) int {
+	return prompts.StartExpand(str(jsonData), str(promptText), str(footerText), str(defaultKey), str(backKey), timeout, resetTimeoutOnKey)
+}
+
+//export StartTags
+func StartTags(jsonData, headerText, footerText *C.char, perPage int, preselectedValues, backKey *C.char, timeout int, resetTimeoutOnKey bool) int {
+	return prompts.StartTags(str(jsonData), str(headerText), str(footerText), perPage, str(preselectedValues), str(backKey), timeout, resetTimeoutOnKey)
+}
+
+//export PollResult
+func PollResult(id int) *C.char {
+	return ch(prompts.PollResult(id))
+}
+
+//export WaitResult
+func WaitResult(id int) *C.char {
+	return ch(prompts.WaitResult(id))
+}
+
+//export CancelPrompt
+func CancelPrompt(id int) bool {
+	return prompts.CancelPrompt(id)
+}
+
+//export CancelActivePrompt
+func CancelActivePrompt(reason *C.char) bool {
+	return prompts.CancelActivePrompt(str(reason))
+}
+
+//export SetConcurrencyPolicy
+func SetConcurrencyPolicy(policy *C.char) bool {
+	return prompts.SetConcurrencyPolicy(str(policy))
+}
+
+//export StartStreamSelection
+func StartStreamSelection(jsonData, headerText, footerText *C.char, perPage int, defaultValue, initialValue, backKey *C.char, timeout int, resetTimeoutOnKey bool) int {
+	return prompts.StartStreamSelection(str(jsonData), str(headerText), str(footerText), perPage, str(defaultValue), str(initialValue), str(backKey), timeout, resetTimeoutOnKey)
+}
+
+//export StreamAppend
+func StreamAppend(id int, jsonData *C.char) bool {
+	return prompts.StreamAppend(id, str(jsonData))
+}
+
+//export StreamUpdate
+func StreamUpdate(id int, jsonData *C.char) bool {
+	return prompts.StreamUpdate(id, str(jsonData))
+}
+
+//export StreamRemove
+func StreamRemove(id int, value *C.char) bool {
+	return prompts.StreamRemove(id, str(value))
+}
+
+//export StreamComplete
+func StreamComplete(id int) bool {
+	return prompts.StreamComplete(id)
+}
+
+//export SetTerminalPath
+func SetTerminalPath(path *C.char) *C.char {
+	if err := prompts.SetTerminalPath(str(path)); err != nil {
+		return ch(err.Error())
+	}
+	return ch("")
+}
@@ -0,0 +1,779 @@
+package prompts
+
+import (
+	"context"
+	"time"
+)
+
+// EchoMode controls how the text typed into an input prompt is shown.
+type EchoMode string
+
+const (
+	EchoNormal   EchoMode = "normal"
+	EchoPassword EchoMode = "password"
+	EchoNone     EchoMode = "none"
+)
+
+// PromptOptions are the settings shared by every prompt type.
+type PromptOptions struct {
+	// Header is the text shown above the prompt (not used by input prompts)
+	Header string
+	// Footer is the text shown below the prompt (not used by input prompts)
+	Footer string
+	// BackKey resolves the prompt with ErrBack when pressed, e.g. "shift+tab"; empty disables it
+	BackKey string
+	// Timeout resolves the prompt with its default, or ErrTimeout without one; 0 disables it
+	Timeout time.Duration
+	// ResetTimeoutOnKey restarts the timeout on every key press
+	ResetTimeoutOnKey bool
+}
+
+func (o *PromptOptions) prompt() *PromptOptions {
+	return o
+}
+
+// timeoutSeconds converts Timeout to the whole seconds counted down by the prompts, rounding up.
+func (o *PromptOptions) timeoutSeconds() int {
+	if o.Timeout <= 0 {
+		return 0
+	}
+	return int((o.Timeout + time.Second - 1) / time.Second)
+}
+
+// SelectOptions configure a single selection.
+type SelectOptions struct {
+	PromptOptions
+	// PerPage is the number of visible items, 5 when 0
+	PerPage int
+	// Autocomplete moves the cursor to the first item matching the typed text
+	Autocomplete bool
+	// Default is the value returned when the user confirms without moving the cursor, and on timeout
+	Default string
+	// Initial is the value the cursor starts on
+	Initial string
+	// other is the label of the "Other…" entry, set by RunOther
+	other string
+}
+
+// MultiselectOptions configure a multiple selection.
+type MultiselectOptions struct {
+	PromptOptions
+	// PerPage is the number of visible items, 5 when 0
+	PerPage int
+	// Autocomplete moves the cursor to the first item matching the typed text. It takes
+	// the letters, so the visual range mode is toggled with Ctrl+V only, not with v
+	Autocomplete bool
+	// Preselected are the values checked initially; they are also returned on timeout
+	Preselected []string
+	// Initial is the value the cursor starts on
+	Initial string
+	// other is the label of the "Other…" entry, set by RunOther
+	other string
+}
+
+// GroupMultiselectOptions configure a multiple selection from grouped items.
+type GroupMultiselectOptions struct {
+	PromptOptions
+	// PerPage is the number of visible items, 10 when 0
+	PerPage int
+	// Autocomplete moves the cursor to the first item matching the typed text. It takes
+	// the letters, so the visual range mode is toggled with Ctrl+V only, not with v
+	Autocomplete bool
+	// SelectableGroups lets a group header toggle all items of its group
+	SelectableGroups bool
+	// Preselected are the values checked initially; they are also returned on timeout
+	Preselected []string
+	// Initial is the value the cursor starts on
+	Initial string
+	// GroupSpacing is the number of blank lines between groups
+	GroupSpacing int
+}
+
+// GroupSelectOptions configure a single selection from grouped items.
+type GroupSelectOptions struct {
+	PromptOptions
+	// PerPage is the number of visible items, 10 when 0
+	PerPage int
+	// Autocomplete moves the cursor to the first item matching the typed text
+	Autocomplete bool
+	// Default is the value returned when the user confirms without moving the cursor, and on timeout
+	Default string
+	// Initial is the value the cursor starts on
+	Initial string
+	// GroupSpacing is the number of blank lines between groups
+	GroupSpacing int
+}
+
+// TagsOptions configure a list of free-form values with suggestions.
+type TagsOptions struct {
+	PromptOptions
+	// PerPage is the number of visible suggestions, 5 when 0
+	PerPage int
+	// Preselected are the initial tags; they are also returned on timeout
+	Preselected []string
+}
+
+// ConfirmOptions configure a yes/no question.
+type ConfirmOptions struct {
+	PromptOptions
+	// Prompt is the question, shown when Header is empty
+	Prompt string
+	// Default is the answer returned when the user confirms without moving the cursor, and on timeout
+	Default *bool
+	// Initial is the answer the cursor starts on
+	Initial *bool
+}
+
+// InputOptions configure a text input.
+type InputOptions struct {
+	PromptOptions
+	// Prompt is the label in front of the text field
+	Prompt string
+	// EchoMode hides the typed text for passwords; EchoNormal when empty
+	EchoMode EchoMode
+	// ValidateOkPrefix and ValidateErrPrefix replace the markers shown for valid and invalid text
+	ValidateOkPrefix  string
+	ValidateErrPrefix string
+	// Default is returned when the text field is left empty, and on timeout
+	Default string
+	// Initial pre-fills the text field
+	Initial string
+	// Required refuses an empty answer
+	Required bool
+	// CharLimit is the maximum length of the answer, 0 for no limit
+	CharLimit int
+}
+
+// FormOptions configure a form.
+type FormOptions struct {
+	PromptOptions
+}
+
+// DangerConfirmOptions configure a confirmation that requires typing a phrase.
+type DangerConfirmOptions struct {
+	PromptOptions
+	// Prompt is the question shown above the text field
+	Prompt string
+	// Phrase is the text that has to be typed exactly to confirm, e.g. a package name
+	Phrase string
+}
+
+// ExpandOptions configure a prompt whose options are chosen by their key.
+type ExpandOptions struct {
+	PromptOptions
+	// Prompt is the question, shown in place of Header
+	Prompt string
+	// Default is the key of the option chosen on Enter, and on timeout
+	Default string
+}
+
+// options is implemented by every option struct through the embedded PromptOptions.
+type options interface {
+	prompt() *PromptOptions
+}
+
+// Option changes one setting of a prompt. Options that do not apply to a prompt type are ignored.
+type Option func(o options)
+
+func applyOptions(o options, opts []Option) {
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+}
+
+// WithHeader sets the text shown above the prompt.
+func WithHeader(header string) Option {
+	return func(o options) {
+		o.prompt().Header = header
+	}
+}
+
+// WithFooter sets the text shown below the prompt.
+func WithFooter(footer string) Option {
+	return func(o options) {
+		o.prompt().Footer = footer
+	}
+}
+
+// WithBackKey sets the key that resolves the prompt with ErrBack.
+func WithBackKey(key string) Option {
+	return func(o options) {
+		o.prompt().BackKey = key
+	}
+}
+
+// WithTimeout resolves the prompt with its default after d, optionally restarting the countdown on every key press.
+func WithTimeout(d time.Duration, resetOnKey bool) Option {
+	return func(o options) {
+		o.prompt().Timeout = d
+		o.prompt().ResetTimeoutOnKey = resetOnKey
+	}
+}
+
+// WithPerPage sets the number of visible items of a selection.
+func WithPerPage(perPage int) Option {
+	return func(o options) {
+		switch o := o.(type) {
+		case *SelectOptions:
+			o.PerPage = perPage
+		case *MultiselectOptions:
+			o.PerPage = perPage
+		case *GroupMultiselectOptions:
+			o.PerPage = perPage
+		case *GroupSelectOptions:
+			o.PerPage = perPage
+		case *TagsOptions:
+			o.PerPage = perPage
+		}
+	}
+}
+
+// WithAutocomplete enables or disables type-to-search in a selection.
+func WithAutocomplete(enabled bool) Option {
+	return func(o options) {
+		switch o := o.(type) {
+		case *SelectOptions:
+			o.Autocomplete = enabled
+		case *MultiselectOptions:
+			o.Autocomplete = enabled
+		case *GroupMultiselectOptions:
+			o.Autocomplete = enabled
+		case *GroupSelectOptions:
+			o.Autocomplete = enabled
+		}
+	}
+}
+
+// WithDefault sets the default value of a selection or an input, or the default key of an expand prompt.
+func WithDefault(value string) Option {
+	return func(o options) {
+		switch o := o.(type) {
+		case *SelectOptions:
+			o.Default = value
+		case *GroupSelectOptions:
+			o.Default = value
+		case *InputOptions:
+			o.Default = value
+		case *ExpandOptions:
+			o.Default = value
+		}
+	}
+}
+
+// WithInitial sets the value the cursor of a selection starts on, or pre-fills an input.
+func WithInitial(value string) Option {
+	return func(o options) {
+		switch o := o.(type) {
+		case *SelectOptions:
+			o.Initial = value
+		case *MultiselectOptions:
+			o.Initial = value
+		case *GroupMultiselectOptions:
+			o.Initial = value
+		case *GroupSelectOptions:
+			o.Initial = value
+		case *InputOptions:
+			o.Initial = value
+		}
+	}
+}
+
+// WithPreselected checks the given values of a multiple selection initially, or sets the initial tags.
+func WithPreselected(values ...string) Option {
+	return func(o options) {
+		switch o := o.(type) {
+		case *MultiselectOptions:
+			o.Preselected = values
+		case *GroupMultiselectOptions:
+			o.Preselected = values
+		case *TagsOptions:
+			o.Preselected = values
+		}
+	}
+}
+
+// WithSelectableGroups lets the group headers of a grouped selection toggle their items.
+func WithSelectableGroups(selectable bool) Option {
+	return func(o options) {
+		if o, ok := o.(*GroupMultiselectOptions); ok {
+			o.SelectableGroups = selectable
+		}
+	}
+}
+
+// WithGroupSpacing sets the number of blank lines between the groups of a grouped selection.
+func WithGroupSpacing(lines int) Option {
+	return func(o options) {
+		switch o := o.(type) {
+		case *GroupMultiselectOptions:
+			o.GroupSpacing = lines
+		case *GroupSelectOptions:
+			o.GroupSpacing = lines
+		}
+	}
+}
+
+// WithDefaultAnswer sets the default answer of a confirmation.
+func WithDefaultAnswer(answer bool) Option {
+	return func(o options) {
+		if o, ok := o.(*ConfirmOptions); ok {
+			o.Default = &answer
+		}
+	}
+}
+
+// WithInitialAnswer sets the answer the cursor of a confirmation starts on.
+func WithInitialAnswer(answer bool) Option {
+	return func(o options) {
+		if o, ok := o.(*ConfirmOptions); ok {
+			o.Initial = &answer
+		}
+	}
+}
+
+// WithEchoMode sets how the text of an input is shown.
+func WithEchoMode(mode EchoMode) Option {
+	return func(o options) {
+		if o, ok := o.(*InputOptions); ok {
+			o.EchoMode = mode
+		}
+	}
+}
+
+// WithRequired refuses an empty input.
+func WithRequired(required bool) Option {
+	return func(o options) {
+		if o, ok := o.(*InputOptions); ok {
+			o.Required = required
+		}
+	}
+}
+
+// WithCharLimit sets the maximum length of an input.
+func WithCharLimit(limit int) Option {
+	return func(o options) {
+		if o, ok := o.(*InputOptions); ok {
+			o.CharLimit = limit
+		}
+	}
+}
+
+// WithValidatePrefixes replaces the markers an input shows for valid and invalid text.
+func WithValidatePrefixes(ok, err string) Option {
+	return func(o options) {
+		if o, isInput := o.(*InputOptions); isInput {
+			o.ValidateOkPrefix = ok
+			o.ValidateErrPrefix = err
+		}
+	}
+}
+
+// Select asks for one of items and returns its index. Autocomplete is enabled unless
+// disabled with WithAutocomplete. With ErrBack the index of the item under the cursor is
+// returned, -1 otherwise on error. Items with children open a submenu, and the index of
+// an answer from a submenu is its index among the children; use SelectPath for the
+// whole path.
+func Select(items []ListItem, opts ...Option) (int, error) {
+	return SelectContext(context.Background(), items, opts...)
+}
+
+// SelectContext is Select bounded by ctx: once ctx is done the prompt is closed and
+// ctx.Err() is returned instead of ErrCancelled.
+func SelectContext(ctx context.Context, items []ListItem, opts ...Option) (int, error) {
+	o := &SelectOptions{Autocomplete: true}
+	applyOptions(o, opts)
+	return o.RunContext(ctx, items)
+}
+
+// Run shows the selection.
+func (o SelectOptions) Run(items []ListItem) (int, error) {
+	return o.RunContext(context.Background(), items)
+}
+
+// RunContext shows the selection until it is answered or ctx is done.
+func (o SelectOptions) RunContext(ctx context.Context, items []ListItem) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return -1, err
+	}
+	s := newPromptSession()
+	stop := s.watch(ctx)
+	index, _, _, err := o.run(s, items)
+	return index, stop(err)
+}
+
+// SelectPath is Select for nested items: it returns the values from the top-level item
+// down to the answer, e.g. ["settings", "network", "proxy"].
+func SelectPath(items []ListItem, opts ...Option) ([]string, error) {
+	return SelectPathContext(context.Background(), items, opts...)
+}
+
+// SelectPathContext is SelectPath bounded by ctx.
+func SelectPathContext(ctx context.Context, items []ListItem, opts ...Option) ([]string, error) {
+	o := &SelectOptions{Autocomplete: true}
+	applyOptions(o, opts)
+	return o.RunPathContext(ctx, items)
+}
+
+// RunPath shows the selection and returns the path of values leading to the answer.
+func (o SelectOptions) RunPath(items []ListItem) ([]string, error) {
+	return o.RunPathContext(context.Background(), items)
+}
+
+// RunPathContext is RunPath bounded by ctx.
+func (o SelectOptions) RunPathContext(ctx context.Context, items []ListItem) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s := newPromptSession()
+	stop := s.watch(ctx)
+	_, path, _, err := o.run(s, items)
+	return path, stop(err)
+}
+
+// SelectOther is Select with an entry labelled other appended to items: choosing it
+// opens a text field and the typed text is returned as custom, with an index of -1.
+func SelectOther(items []ListItem, other string, opts ...Option) (index int, custom string, err error) {
+	return SelectOtherContext(context.Background(), items, other, opts...)
+}
+
+// SelectOtherContext is SelectOther bounded by ctx.
+func SelectOtherContext(ctx context.Context, items []ListItem, other string, opts ...Option) (int, string, error) {
+	o := &SelectOptions{Autocomplete: true}
+	applyOptions(o, opts)
+	return o.RunOtherContext(ctx, items, other)
+}
+
+// RunOther shows the selection with an entry labelled other for typing a custom value.
+func (o SelectOptions) RunOther(items []ListItem, other string) (int, string, error) {
+	return o.RunOtherContext(context.Background(), items, other)
+}
+
+// RunOtherContext is RunOther bounded by ctx.
+func (o SelectOptions) RunOtherContext(ctx context.Context, items []ListItem, other string) (int, string, error) {
+	if err := ctx.Err(); err != nil {
+		return -1, "", err
+	}
+	o.other = other
+	s := newPromptSession()
+	stop := s.watch(ctx)
+	index, _, custom, err := o.run(s, items)
+	return index, custom, stop(err)
+}
+
+// SelectMany asks for any number of items and returns their indices in ascending order.
+// Autocomplete is enabled unless disabled with WithAutocomplete. With ErrBack the
+// checked items are returned.
+func SelectMany(items []ListItem, opts ...Option) ([]int, error) {
+	return SelectManyContext(context.Background(), items, opts...)
+}
+
+// SelectManyContext is SelectMany bounded by ctx: once ctx is done the prompt is closed
+// and ctx.Err() is returned instead of ErrCancelled.
+func SelectManyContext(ctx context.Context, items []ListItem, opts ...Option) ([]int, error) {
+	o := &MultiselectOptions{Autocomplete: true}
+	applyOptions(o, opts)
+	return o.RunContext(ctx, items)
+}
+
+// Run shows the multiple selection.
+func (o MultiselectOptions) Run(items []ListItem) ([]int, error) {
+	return o.RunContext(context.Background(), items)
+}
+
+// RunContext shows the multiple selection until it is answered or ctx is done.
+func (o MultiselectOptions) RunContext(ctx context.Context, items []ListItem) ([]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s := newPromptSession()
+	stop := s.watch(ctx)
+	indices, _, err := o.run(s, items)
+	return indices, stop(err)
+}
+
+// SelectManyOther is SelectMany with an entry labelled other appended to items: checking
+// it opens a text field and the typed text is returned as custom while it stays checked.
+func SelectManyOther(items []ListItem, other string, opts ...Option) (indices []int, custom string, err error) {
+	return SelectManyOtherContext(context.Background(), items, other, opts...)
+}
+
+// SelectManyOtherContext is SelectManyOther bounded by ctx.
+func SelectManyOtherContext(ctx context.Context, items []ListItem, other string, opts ...Option) ([]int, string, error) {
+	o := &MultiselectOptions{Autocomplete: true}
+	applyOptions(o, opts)
+	return o.RunOtherContext(ctx, items, other)
+}
+
+// RunOther shows the multiple selection with an entry labelled other for typing a custom value.
+func (o MultiselectOptions) RunOther(items []ListItem, other string) ([]int, string, error) {
+	return o.RunOtherContext(context.Background(), items, other)
+}
+
+// RunOtherContext is RunOther bounded by ctx.
+func (o MultiselectOptions) RunOtherContext(ctx context.Context, items []ListItem, other string) ([]int, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+	o.other = other
+	s := newPromptSession()
+	stop := s.watch(ctx)
+	indices, custom, err := o.run(s, items)
+	return indices, custom, stop(err)
+}
+
+// SelectGrouped asks for any number of grouped items and returns their indices in
+// ascending order; group headers are never returned. Autocomplete is enabled unless
+// disabled with WithAutocomplete. With ErrBack the checked items are returned.
+func SelectGrouped(items []GroupListItem, opts ...Option) ([]int, error) {
+	return SelectGroupedContext(context.Background(), items, opts...)
+}
+
+// SelectGroupedContext is SelectGrouped bounded by ctx: once ctx is done the prompt is
+// closed and ctx.Err() is returned instead of ErrCancelled.
+func SelectGroupedContext(ctx context.Context, items []GroupListItem, opts ...Option) ([]int, error) {
+	o := &GroupMultiselectOptions{Autocomplete: true}
+	applyOptions(o, opts)
+	return o.RunContext(ctx, items)
+}
+
+// Run shows the grouped multiple selection.
+func (o GroupMultiselectOptions) Run(items []GroupListItem) ([]int, error) {
+	return o.RunContext(context.Background(), items)
+}
+
+// RunContext shows the grouped multiple selection until it is answered or ctx is done.
+func (o GroupMultiselectOptions) RunContext(ctx context.Context, items []GroupListItem) ([]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s := newPromptSession()
+	stop := s.watch(ctx)
+	indices, err := o.run(s, items)
+	return indices, stop(err)
+}
+
+// SelectGroupedOne asks for one of the grouped items and returns its index; group
+// headers are skipped and never returned. Autocomplete is enabled unless disabled with
+// WithAutocomplete.
+func SelectGroupedOne(items []GroupListItem, opts ...Option) (int, error) {
+	return SelectGroupedOneContext(context.Background(), items, opts...)
+}
+
+// SelectGroupedOneContext is SelectGroupedOne bounded by ctx: once ctx is done the prompt
+// is closed and ctx.Err() is returned instead of ErrCancelled.
+func SelectGroupedOneContext(ctx context.Context, items []GroupListItem, opts ...Option) (int, error) {
+	o := &GroupSelectOptions{Autocomplete: true}
+	applyOptions(o, opts)
+	return o.RunContext(ctx, items)
+}
+
+// Run shows the grouped single selection.
+func (o GroupSelectOptions) Run(items []GroupListItem) (int, error) {
+	return o.RunContext(context.Background(), items)
+}
+
+// RunContext shows the grouped single selection until it is answered or ctx is done.
+func (o GroupSelectOptions) RunContext(ctx context.Context, items []GroupListItem) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return -1, err
+	}
+	s := newPromptSession()
+	stop := s.watch(ctx)
+	index, err := o.run(s, items)
+	return index, stop(err)
+}
+
+// AskTags asks for a list of values: typed text becomes a new tag on Enter and
+// suggestions can be picked with the arrow keys. Tags are returned in the order they
+// were added. With ErrBack the current tags are returned.
+func AskTags(suggestions []ListItem, opts ...Option) ([]string, error) {
+	return AskTagsContext(context.Background(), suggestions, opts...)
+}
+
+// AskTagsContext is AskTags bounded by ctx: once ctx is done the prompt is closed and
+// ctx.Err() is returned instead of ErrCancelled.
+func AskTagsContext(ctx context.Context, suggestions []ListItem, opts ...Option) ([]string, error) {
+	o := &TagsOptions{}
+	applyOptions(o, opts)
+	return o.RunContext(ctx, suggestions)
+}
+
+// Run shows the tags prompt.
+func (o TagsOptions) Run(suggestions []ListItem) ([]string, error) {
+	return o.RunContext(context.Background(), suggestions)
+}
+
+// RunContext shows the tags prompt until it is answered or ctx is done.
+func (o TagsOptions) RunContext(ctx context.Context, suggestions []ListItem) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s := newPromptSession()
+	stop := s.watch(ctx)
+	values, err := o.run(s, suggestions)
+	return values, stop(err)
+}
+
+// AskConfirm asks a yes/no question. With ErrBack the answer under the cursor is returned.
+func AskConfirm(prompt string, opts ...Option) (bool, error) {
+	return AskConfirmContext(context.Background(), prompt, opts...)
+}
+
+// AskConfirmContext is AskConfirm bounded by ctx: once ctx is done the prompt is closed
+// and ctx.Err() is returned instead of ErrCancelled.
+func AskConfirmContext(ctx context.Context, prompt string, opts ...Option) (bool, error) {
+	o := &ConfirmOptions{Prompt: prompt}
+	applyOptions(o, opts)
+	return o.RunContext(ctx)
+}
+
+// Run shows the confirmation.
+func (o ConfirmOptions) Run() (bool, error) {
+	return o.RunContext(context.Background())
+}
+
+// RunContext shows the confirmation until it is answered or ctx is done.
+func (o ConfirmOptions) RunContext(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	s := newPromptSession()
+	stop := s.watch(ctx)
+	answer, err := o.run(s)
+	return answer, stop(err)
+}
+
+// AskInput asks for a line of text. With ErrBack the text typed so far is returned.
+func AskInput(prompt string, opts ...Option) (string, error) {
+	return AskInputContext(context.Background(), prompt, opts...)
+}
+
+// AskInputContext is AskInput bounded by ctx: once ctx is done the prompt is closed and
+// ctx.Err() is returned instead of ErrCancelled.
+func AskInputContext(ctx context.Context, prompt string, opts ...Option) (string, error) {
+	o := &InputOptions{Prompt: prompt}
+	applyOptions(o, opts)
+	return o.RunContext(ctx)
+}
+
+// Run shows the input.
+func (o InputOptions) Run() (string, error) {
+	return o.RunContext(context.Background())
+}
+
+// RunContext shows the input until it is answered or ctx is done.
+func (o InputOptions) RunContext(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	s := newPromptSession()
+	stop := s.watch(ctx)
+	value, err := o.run(s)
+	return value, stop(err)
+}
+
+// AskForm asks the questions of fields one after another and returns the answers by
+// field name. With ErrBack the answers given so far are returned.
+func AskForm(fields []FormField, opts ...Option) (map[string]interface{}, error) {
+	return AskFormContext(context.Background(), fields, opts...)
+}
+
+// AskFormContext is AskForm bounded by ctx: once ctx is done the prompt is closed and
+// ctx.Err() is returned instead of ErrCancelled.
+func AskFormContext(ctx context.Context, fields []FormField, opts ...Option) (map[string]interface{}, error) {
+	o := &FormOptions{}
+	applyOptions(o, opts)
+	return o.RunContext(ctx, fields)
+}
+
+// Run shows the form.
+func (o FormOptions) Run(fields []FormField) (map[string]interface{}, error) {
+	return o.RunContext(context.Background(), fields)
+}
+
+// RunContext shows the form until it is submitted or ctx is done.
+func (o FormOptions) RunContext(ctx context.Context, fields []FormField) (map[string]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s := newPromptSession()
+	stop := s.watch(ctx)
+	values, err := o.run(s, fields)
+	return values, stop(err)
+}
+
+// AskDangerConfirm asks the user to type phrase exactly before a destructive action. It
+// returns true only on an exact match and false on Esc; a timeout never confirms.
+func AskDangerConfirm(prompt, phrase string, opts ...Option) (bool, error) {
+	return AskDangerConfirmContext(context.Background(), prompt, phrase, opts...)
+}
+
+// AskDangerConfirmContext is AskDangerConfirm bounded by ctx: once ctx is done the prompt
+// is closed and ctx.Err() is returned instead of ErrCancelled.
+func AskDangerConfirmContext(ctx context.Context, prompt, phrase string, opts ...Option) (bool, error) {
+	o := &DangerConfirmOptions{Prompt: prompt, Phrase: phrase}
+	applyOptions(o, opts)
+	return o.RunContext(ctx)
+}
+
+// Run shows the confirmation.
+func (o DangerConfirmOptions) Run() (bool, error) {
+	return o.RunContext(context.Background())
+}
+
+// RunContext shows the confirmation until it is answered or ctx is done.
+func (o DangerConfirmOptions) RunContext(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	s := newPromptSession()
+	stop := s.watch(ctx)
+	confirmed, err := o.run(s)
+	return confirmed, stop(err)
+}
+
+// AskExpand asks for one of items, chosen by pressing its key; "?" lists the options with
+// their labels and Enter picks the option set with WithDefault. It returns the value of
+// the chosen option.
+func AskExpand(items []ExpandItem, prompt string, opts ...Option) (string, error) {
+	return AskExpandContext(context.Background(), items, prompt, opts...)
+}
+
+// AskExpandContext is AskExpand bounded by ctx: once ctx is done the prompt is closed and
+// ctx.Err() is returned instead of ErrCancelled.
+func AskExpandContext(ctx context.Context, items []ExpandItem, prompt string, opts ...Option) (string, error) {
+	o := &ExpandOptions{Prompt: prompt}
+	applyOptions(o, opts)
+	return o.RunContext(ctx, items)
+}
+
+// Run shows the expand prompt.
+func (o ExpandOptions) Run(items []ExpandItem) (string, error) {
+	return o.RunContext(context.Background(), items)
+}
+
+// RunContext shows the expand prompt until it is answered or ctx is done.
+func (o ExpandOptions) RunContext(ctx context.Context, items []ExpandItem) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	s := newPromptSession()
+	stop := s.watch(ctx)
+	value, err := o.run(s, items)
+	return value, stop(err)
+}
+
+// parseAnswer converts the "true"/"false" strings of the string based API, nil for anything else.
+func parseAnswer(value string) *bool {
+	switch value {
+	case "true":
+		answer := true
+		return &answer
+	case "false":
+		answer := false
+		return &answer
+	}
+	return nil
+}
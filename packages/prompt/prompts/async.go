@@ -0,0 +1,171 @@
+package prompts
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// asyncPrompt is a prompt started in the background, identified by a handle.
+type asyncPrompt struct {
+	session *promptSession
+	done    chan struct{}
+	result  string
+}
+
+var asyncPrompts = struct {
+	sync.Mutex
+	nextID  int
+	running map[int]*asyncPrompt
+}{running: make(map[int]*asyncPrompt)}
+
+// unknownHandleResult is returned for handles that do not exist or were already collected.
+func unknownHandleResult() string {
+	text, code, message, _ := errorFields(&PromptError{Code: CodeInvalidHandle, Message: "unknown prompt handle"})
+	result, _ := json.Marshal(&struct {
+		Error   string `json:"error"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}{Error: text, Code: code, Message: message})
+	return string(result)
+}
+
+// startAsync runs fn on its own goroutine and returns the handle to collect its result with.
+func startAsync(fn func(s *promptSession) string) int {
+	prompt := &asyncPrompt{
+		session: newPromptSession(),
+		done:    make(chan struct{}),
+	}
+
+	asyncPrompts.Lock()
+	asyncPrompts.nextID++
+	id := asyncPrompts.nextID
+	asyncPrompts.running[id] = prompt
+	asyncPrompts.Unlock()
+
+	go func() {
+		defer close(prompt.done)
+		prompt.result = fn(prompt.session)
+	}()
+	return id
+}
+
+func lookupAsync(id int) (*asyncPrompt, bool) {
+	asyncPrompts.Lock()
+	defer asyncPrompts.Unlock()
+	prompt, ok := asyncPrompts.running[id]
+	return prompt, ok
+}
+
+func forgetAsync(id int) {
+	asyncPrompts.Lock()
+	defer asyncPrompts.Unlock()
+	delete(asyncPrompts.running, id)
+}
+
+// PollResult returns the result JSON of a background prompt, or "" while it is still open.
+// A handle can be collected only once.
+func PollResult(id int) string {
+	prompt, ok := lookupAsync(id)
+	if !ok {
+		return unknownHandleResult()
+	}
+	select {
+	case <-prompt.done:
+		forgetAsync(id)
+		return prompt.result
+	default:
+		return ""
+	}
+}
+
+// WaitResult blocks until a background prompt is done and returns its result JSON.
+func WaitResult(id int) string {
+	prompt, ok := lookupAsync(id)
+	if !ok {
+		return unknownHandleResult()
+	}
+	<-prompt.done
+	forgetAsync(id)
+	return prompt.result
+}
+
+// CancelPrompt closes a background prompt, which then resolves with a "Cancelled" error.
+// It returns false for unknown handles.
+func CancelPrompt(id int) bool {
+	prompt, ok := lookupAsync(id)
+	if !ok {
+		return false
+	}
+	prompt.session.cancel("")
+	return true
+}
+
+// StartSelection is the non-blocking variant of Selection.
+func StartSelection(jsonData, headerText, footerText string, perPage int, autocomplete bool, defaultValue, initialValue, backKey string, timeout int, resetTimeoutOnKey bool, otherLabel string) int {
+	return startAsync(func(s *promptSession) string {
+		return selection(s, jsonData, headerText, footerText, perPage, autocomplete, defaultValue, initialValue, backKey, timeout, resetTimeoutOnKey, otherLabel)
+	})
+}
+
+// StartInput is the non-blocking variant of Input.
+func StartInput(promptText, echoMode, validateOkPrefix, validateErrPrefix, defaultValue, initialValue string, required bool, charLimit int, backKey string, timeout int, resetTimeoutOnKey bool) int {
+	return startAsync(func(s *promptSession) string {
+		return input(s, promptText, echoMode, validateOkPrefix, validateErrPrefix, defaultValue, initialValue, required, charLimit, backKey, timeout, resetTimeoutOnKey)
+	})
+}
+
+// StartMultiselect is the non-blocking variant of Multiselect.
+func StartMultiselect(jsonData, headerText, footerText string, perPage int, autocomplete bool, preselectedValues, initialCursorValue, backKey string, timeout int, resetTimeoutOnKey bool, otherLabel string) int {
+	return startAsync(func(s *promptSession) string {
+		return multiselect(s, jsonData, headerText, footerText, perPage, autocomplete, preselectedValues, initialCursorValue, backKey, timeout, resetTimeoutOnKey, otherLabel)
+	})
+}
+
+// StartConfirm is the non-blocking variant of Confirm.
+func StartConfirm(promptText, headerText, footerText string, defaultValue, initialValue, backKey string, timeout int, resetTimeoutOnKey bool) int {
+	return startAsync(func(s *promptSession) string {
+		return confirm(s, promptText, headerText, footerText, defaultValue, initialValue, backKey, timeout, resetTimeoutOnKey)
+	})
+}
+
+// StartGroupMultiselect is the non-blocking variant of GroupMultiselect.
+func StartGroupMultiselect(jsonData, headerText, footerText string, perPage int, autocomplete, selectableGroups bool, preselectedValues, initialCursorValue string, groupSpacing int, backKey string, timeout int, resetTimeoutOnKey bool) int {
+	return startAsync(func(s *promptSession) string {
+		return groupMultiselect(s, jsonData, headerText, footerText, perPage, autocomplete, selectableGroups, preselectedValues, initialCursorValue, groupSpacing, backKey, timeout, resetTimeoutOnKey)
+	})
+}
+
+// StartGroupSelect is the non-blocking variant of GroupSelect.
+func StartGroupSelect(jsonData, headerText, footerText string, perPage int, autocomplete bool, defaultValue, initialValue string, groupSpacing int, backKey string, timeout int, resetTimeoutOnKey bool) int {
+	return startAsync(func(s *promptSession) string {
+		return groupSelect(s, jsonData, headerText, footerText, perPage, autocomplete, defaultValue, initialValue, groupSpacing, backKey, timeout, resetTimeoutOnKey)
+	})
+}
+
+// StartForm is the non-blocking variant of Form.
+func StartForm(jsonData, headerText, footerText, backKey string, timeout int, resetTimeoutOnKey bool) int {
+	return startAsync(func(s *promptSession) string {
+		return form(s, jsonData, headerText, footerText, backKey, timeout, resetTimeoutOnKey)
+	})
+}
+
+// StartDangerConfirm is the non-blocking variant of DangerConfirm.
+func StartDangerConfirm(promptText, headerText, footerText, phrase, backKey string, timeout int, resetTimeoutOnKey bool) int {
+	return startAsync(func(s *promptSession) string {
+		return dangerConfirm(s, promptText, headerText, footerText, phrase, backKey, timeout, resetTimeoutOnKey)
+	})
+}
+
+// StartExpand is the non-blocking variant of Expand.
+func StartExpand(jsonData, promptText, footerText, defaultKey, backKey string, timeout int, resetTimeoutOnKey bool) int {
+	return startAsync(func(s *promptSession) string {
+		return expand(s, jsonData, promptText, footerText, defaultKey, backKey, timeout, resetTimeoutOnKey)
+	})
+}
+
+// StartTags is the non-blocking variant of Tags.
+func StartTags(jsonData, headerText, footerText string, perPage int, preselectedValues, backKey string, timeout int, resetTimeoutOnKey bool) int {
+	return startAsync(func(s *promptSession) string {
+		return tags(s, jsonData, headerText, footerText, perPage, preselectedValues, backKey, timeout, resetTimeoutOnKey)
+	})
+}
@@ -0,0 +1,31 @@
+package prompts
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// backError is the error returned when the user presses the configured back key.
+// The result payload still carries the partial value of the prompt so the caller
+// can re-open the previous step and later restore this one.
+const backError = "Back"
+
+// normalizeBackKey converts a user supplied key binding (e.g. "Shift+Tab", "ctrl+b")
+// to the representation used by tea.KeyMsg.String(). Single characters keep their case.
+func normalizeBackKey(backKey string) string {
+	backKey = strings.TrimSpace(backKey)
+	if len([]rune(backKey)) <= 1 {
+		return backKey
+	}
+	return strings.ToLower(backKey)
+}
+
+// isBackKey reports whether msg matches the configured back key.
+// An empty backKey disables back navigation.
+func isBackKey(msg tea.KeyMsg, backKey string) bool {
+	if backKey == "" {
+		return false
+	}
+	return msg.String() == normalizeBackKey(backKey)
+}
@@ -3,7 +3,7 @@ package prompts
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"strconv"
 	"time"
 
 	"github.com/mritd/bubbles/common"
@@ -11,7 +11,6 @@ import (
 	"github.com/mritd/bubbles/selector"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"golang.org/x/term"
 )
 
 type confirmModel struct {
@@ -20,15 +19,28 @@ type confirmModel struct {
 	ctrlCPressTime   time.Time
 	showCancelMsg    bool
 	canceled         bool
+	backKey          string
+	wentBack         bool
+	timeout          promptTimeout
+	hotkeyValue      string
+	// startIndex is the option the cursor started on; index 0 = Yes, index 1 = No
+	startIndex    int
+	defaultAnswer *bool
+	// timeoutAnswer is returned on timeout, nil for none
+	timeoutAnswer *bool
 }
 
 func (m confirmModel) Init() tea.Cmd {
-	return nil
+	return m.timeout.init()
 }
 
 type confirmResetCancelMsg struct{}
 
 func (m *confirmModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if handled, cmd := m.timeout.update(msg); handled {
+		return m, cmd
+	}
+
 	// Handle double Ctrl+C first - must intercept before selector sees it
 	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "ctrl+c" {
 		now := time.Now()
@@ -56,6 +68,24 @@ func (m *confirmModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Handle back navigation before the selector sees the key
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && isBackKey(keyMsg, m.backKey) {
+		m.wentBack = true
+		return m, tea.Quit
+	}
+
+	// y/n answer immediately without moving the cursor
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "y", "Y":
+			m.hotkeyValue = "true"
+			return m, tea.Quit
+		case "n", "N":
+			m.hotkeyValue = "false"
+			return m, tea.Quit
+		}
+	}
+
 	switch msg {
 	case common.DONE:
 		return m, tea.Quit
@@ -67,6 +97,7 @@ func (m *confirmModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m confirmModel) View() string {
 	view := m.sl.View()
+	view += m.timeout.view()
 	if m.showCancelMsg {
 		view += "\n" + common.FontColor("Press Ctrl+C again to exit", "yellow")
 	}
@@ -75,136 +106,82 @@ func (m confirmModel) View() string {
 
 type ConfirmResult struct {
 	Confirmed string `json:"confirmed"`
-	Error     string `json:"error"`
+	resultMeta
 }
 
-type confirmWaitForResizeModel struct {
-	minHeight int
-	message   string
+func Confirm(promptText, headerText, footerText string, defaultValue, initialValue, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	return confirm(newPromptSession(), promptText, headerText, footerText, defaultValue, initialValue, backKey, timeout, resetTimeoutOnKey)
 }
 
-func (m confirmWaitForResizeModel) Init() tea.Cmd {
-	return tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
-		return confirmCheckTerminalSizeMsg{}
-	})
-}
-
-type confirmCheckTerminalSizeMsg struct{}
-
-func (m confirmWaitForResizeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		if msg.Height >= m.minHeight {
-			return m, tea.Quit
-		}
-		return m, tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
-			return confirmCheckTerminalSizeMsg{}
-		})
-	case confirmCheckTerminalSizeMsg:
-		fd := int(os.Stdout.Fd())
-		_, height, err := term.GetSize(fd)
-		if err == nil && height >= m.minHeight {
-			return m, tea.Quit
-		}
-		return m, tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
-			return confirmCheckTerminalSizeMsg{}
-		})
-	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" {
-			return m, tea.Quit
-		}
+func confirm(s *promptSession, promptText, headerText, footerText string, defaultValue, initialValue, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	o := ConfirmOptions{
+		PromptOptions: PromptOptions{
+			Header:            headerText,
+			Footer:            footerText,
+			BackKey:           backKey,
+			Timeout:           time.Duration(timeout) * time.Second,
+			ResetTimeoutOnKey: resetTimeoutOnKey,
+		},
+		Prompt:  promptText,
+		Default: parseAnswer(defaultValue),
+		Initial: parseAnswer(initialValue),
 	}
-	return m, nil
-}
-
-func (m confirmWaitForResizeModel) View() string {
-	fd := int(os.Stdout.Fd())
-	_, height, err := term.GetSize(fd)
-	if err != nil {
-		return fmt.Sprintf("Error checking terminal size: %s\n", err)
+	answer, err := o.run(s)
+	confirmed := ""
+	if err == nil || err == ErrBack {
+		confirmed = strconv.FormatBool(answer)
 	}
-	return fmt.Sprintf("\n%s\n\nCurrent height: %d | Required: %d\n\nPlease resize your terminal window to continue...\n", m.message, height, m.minHeight)
+	result, _ := json.Marshal(&ConfirmResult{
+		Confirmed:  confirmed,
+		resultMeta: s.meta(err),
+	})
+	return string(result)
 }
 
-func confirmWaitForTerminalResize(minHeight int, message string) error {
-	m := confirmWaitForResizeModel{
-		minHeight: minHeight,
-		message:   message,
-	}
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	err := p.Start()
+func (o ConfirmOptions) run(s *promptSession) (bool, error) {
+	const minTerminalHeight = 5
+
+	m := o.newModel()
+	err := s.run(m, minTerminalHeight)
 	if err != nil {
-		return err
+		return false, err
 	}
-
-	// Block until terminal is large enough
-	fd := int(os.Stdout.Fd())
-	for {
-		_, height, err := term.GetSize(fd)
-		if err == nil && height >= minHeight {
-			break
-		}
-		time.Sleep(time.Second / 2)
+	if s.cancelled() {
+		return false, ErrCancelled
 	}
-	return nil
+	return m.outcome()
 }
 
-func Confirm(promptText, headerText, footerText string, defaultValue, initialValue string) string {
-	const minTerminalHeight = 5
-
-	var err error
-
-	if shouldValidateTerminalSize() {
-		height, sizeErr := getTerminalHeight()
-		if sizeErr != nil {
-			result, _ := json.Marshal(&ConfirmResult{
-				Confirmed: "",
-				Error:     fmt.Sprintf("failed to get terminal size: %s", sizeErr),
-			})
-			return string(result)
-		}
-
-		if height < minTerminalHeight {
-			// Wait for user to resize terminal instead of returning error
-			waitMessage := fmt.Sprintf("⚠️  Terminal height too small!\n   Current: %d lines | Required: %d lines", height, minTerminalHeight)
-			err = confirmWaitForTerminalResize(minTerminalHeight, waitMessage)
-			if err != nil {
-				result, _ := json.Marshal(&ConfirmResult{
-					Confirmed: "",
-					Error:     fmt.Sprintf("failed to wait for terminal resize: %s", err),
-				})
-				return string(result)
-			}
-		}
-	}
-
+// newModel builds the Yes/No model of the confirmation.
+func (o ConfirmOptions) newModel() *confirmModel {
 	// Create Yes/No items
 	data := []interface{}{
 		ListItem{Value: "yes", Label: "Yes", Hint: ""},
 		ListItem{Value: "no", Label: "No", Hint: ""},
 	}
 
-	header := headerText
+	header := o.Header
 	if header == "" {
-		header = promptText
+		header = o.Prompt
 	}
+	footerText := o.Footer
 
-	// Determine start index based on initialValue or defaultValue
+	// Determine start index based on the initial or default answer; index 0 = Yes, index 1 = No
 	startIndex := 0
-	if initialValue == "true" {
-		startIndex = 0 // Yes
-	} else if initialValue == "false" {
-		startIndex = 1 // No
-	} else if defaultValue == "true" {
-		startIndex = 0 // Yes
-	} else if defaultValue == "false" {
-		startIndex = 1 // No
+	if o.Initial != nil {
+		if !*o.Initial {
+			startIndex = 1
+		}
+	} else if o.Default != nil && !*o.Default {
+		startIndex = 1
 	}
 
 	m := &confirmModel{
 		ctrlCPressedOnce: false,
 		showCancelMsg:    false,
 		canceled:         false,
+		backKey:          o.BackKey,
+		timeout:          newPromptTimeout(o.timeoutSeconds(), o.ResetTimeoutOnKey),
 		sl: selector.Model{
 			Data:       data,
 			PerPage:    2,
@@ -220,7 +197,7 @@ func Confirm(promptText, headerText, footerText string, defaultValue, initialVal
 			FooterFunc: func(m selector.Model, obj interface{}, gdIndex int) string {
 				footer := footerText
 				if footer == "" {
-					footer = "Use arrow keys to navigate, Enter to confirm"
+					footer = "Use arrow keys to navigate, Enter to confirm, y/n to answer"
 				}
 				return common.FontColor(footer, selector.ColorFooter)
 			},
@@ -230,47 +207,46 @@ func Confirm(promptText, headerText, footerText string, defaultValue, initialVal
 		},
 	}
 
-	// Set initial index
-	if startIndex > 0 {
-		for i := 0; i < startIndex; i++ {
-			m.sl.Update(tea.KeyMsg{Type: tea.KeyDown})
-		}
+	// On timeout, resolve with the default answer, falling back to the initial one
+	timeoutValue := o.Default
+	if timeoutValue == nil {
+		timeoutValue = o.Initial
 	}
+	m.timeout.hasDefault = timeoutValue != nil
+	m.startIndex = startIndex
+	m.defaultAnswer = o.Default
+	m.timeoutAnswer = timeoutValue
 
-	p := tea.NewProgram(m)
-	err = p.Start()
-	if err != nil {
-		result, _ := json.Marshal(&ConfirmResult{
-			Confirmed: "",
-			Error:     fmt.Sprintf("%s", err),
-		})
-		return string(result)
+	// Set initial index
+	// Add +1 to account for the initial position (the first update only initializes the selector)
+	for i := 0; i < startIndex+1; i++ {
+		m.sl.Update(tea.KeyMsg{Type: tea.KeyDown})
 	}
-	if !m.canceled && !m.sl.Canceled() {
-		selectedIndex := m.sl.Index()
-		// If user didn't change selection from initial position and defaultValue is provided, use it
-		if defaultValue != "" && selectedIndex == startIndex {
-			// Use defaultValue
-			result, _ := json.Marshal(&ConfirmResult{
-				Confirmed: defaultValue,
-				Error:     "",
-			})
-			return string(result)
-		}
-		// Index 0 = Yes, Index 1 = No
-		confirmed := "false"
-		if selectedIndex == 0 {
-			confirmed = "true"
+
+	return m
+}
+
+// outcome is the answer of the confirmation once its program has quit.
+func (m *confirmModel) outcome() (bool, error) {
+	if m.timeout.expired {
+		if m.timeoutAnswer == nil {
+			return false, ErrTimeout
 		}
-		result, _ := json.Marshal(&ConfirmResult{
-			Confirmed: confirmed,
-			Error:     "",
-		})
-		return string(result)
+		return *m.timeoutAnswer, nil
 	}
-	result, _ := json.Marshal(&ConfirmResult{
-		Confirmed: "",
-		Error:     "Cancelled",
-	})
-	return string(result)
+	if m.wentBack {
+		// Report the option under the cursor as the partial value
+		return m.sl.Index() == 0, ErrBack
+	}
+	if m.hotkeyValue != "" {
+		return m.hotkeyValue == "true", nil
+	}
+	if m.canceled || m.sl.Canceled() {
+		return false, ErrCancelled
+	}
+	// If user didn't change selection from initial position and a default is provided, use it
+	if m.defaultAnswer != nil && m.sl.Index() == m.startIndex {
+		return *m.defaultAnswer, nil
+	}
+	return m.sl.Index() == 0, nil
 }
@@ -0,0 +1,227 @@
+package prompts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mritd/bubbles/common"
+
+	"github.com/mritd/bubbles/selector"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	dangerColor      = "1"
+	dangerMatchColor = "2"
+)
+
+type dangerConfirmModel struct {
+	phrase           []rune
+	typed            []rune
+	promptText       string
+	headerText       string
+	footerText       string
+	showMismatch     bool
+	confirmed        bool
+	ctrlCPressedOnce bool
+	ctrlCPressTime   time.Time
+	showCancelMsg    bool
+	canceled         bool
+	backKey          string
+	wentBack         bool
+	timeout          promptTimeout
+}
+
+func (m *dangerConfirmModel) Init() tea.Cmd {
+	return m.timeout.init()
+}
+
+type dangerConfirmResetCancelMsg struct{}
+
+func (m *dangerConfirmModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if handled, cmd := m.timeout.update(msg); handled {
+		return m, cmd
+	}
+
+	// Handle double Ctrl+C first
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "ctrl+c" {
+		now := time.Now()
+		if m.ctrlCPressedOnce && now.Sub(m.ctrlCPressTime) < 2*time.Second {
+			// Second Ctrl+C within 2 seconds - actually cancel
+			m.canceled = true
+			return m, tea.Quit
+		}
+		// First Ctrl+C - show message and set timer
+		m.ctrlCPressedOnce = true
+		m.ctrlCPressTime = now
+		m.showCancelMsg = true
+		// Reset after 2 seconds
+		return m, tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
+			return dangerConfirmResetCancelMsg{}
+		})
+	}
+
+	// Handle reset message
+	if _, ok := msg.(dangerConfirmResetCancelMsg); ok {
+		// Reset cancel state after timeout
+		m.ctrlCPressedOnce = false
+		m.showCancelMsg = false
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if isBackKey(keyMsg, m.backKey) {
+		m.wentBack = true
+		return m, tea.Quit
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyEnter:
+		// Only an exact match resolves true
+		if string(m.typed) == string(m.phrase) {
+			m.confirmed = true
+			return m, tea.Quit
+		}
+		m.showMismatch = true
+	case tea.KeyEsc:
+		// Abort without confirming
+		return m, tea.Quit
+	case tea.KeyBackspace:
+		if len(m.typed) > 0 {
+			m.typed = m.typed[:len(m.typed)-1]
+		}
+	case tea.KeyCtrlU:
+		m.typed = nil
+	case tea.KeySpace:
+		m.typed = append(m.typed, ' ')
+	case tea.KeyRunes:
+		m.typed = append(m.typed, keyMsg.Runes...)
+	}
+	return m, nil
+}
+
+// typedView colors the matching prefix of the typed text and everything after the first mismatch.
+func (m *dangerConfirmModel) typedView() string {
+	matched := 0
+	for matched < len(m.typed) && matched < len(m.phrase) && m.typed[matched] == m.phrase[matched] {
+		matched++
+	}
+	view := common.FontColor(string(m.typed[:matched]), dangerMatchColor)
+	if matched < len(m.typed) {
+		view += common.FontColor(string(m.typed[matched:]), dangerColor)
+	}
+	return view
+}
+
+func (m *dangerConfirmModel) View() string {
+	var b strings.Builder
+
+	title := m.headerText
+	if title == "" {
+		title = m.promptText
+	}
+	b.WriteString(common.FontColor("⚠  "+title, dangerColor) + "\n")
+	if m.headerText != "" && m.promptText != "" {
+		b.WriteString("   " + m.promptText + "\n")
+	}
+	b.WriteString(fmt.Sprintf("\nType %s to confirm:\n", common.FontColor(string(m.phrase), dangerColor)))
+	b.WriteString(common.FontColor(selector.DefaultCursor, dangerColor) + " " + m.typedView() + common.FontColor("█", selector.ColorUnSelected) + "\n")
+
+	switch {
+	case string(m.typed) == string(m.phrase):
+		b.WriteString(common.FontColor("✔ Phrase matches, press Enter to continue", dangerMatchColor) + "\n")
+	case len(m.typed) > 0 && !strings.HasPrefix(string(m.phrase), string(m.typed)):
+		b.WriteString(common.FontColor("✘ Does not match", dangerColor) + "\n")
+	case m.showMismatch:
+		b.WriteString(common.FontColor("✘ Type the phrase exactly to continue", dangerColor) + "\n")
+	}
+
+	footer := m.footerText
+	if footer == "" {
+		footer = "Enter: confirm, Esc: abort"
+	}
+	b.WriteString("\n" + common.FontColor(footer, selector.ColorFooter))
+	b.WriteString(m.timeout.view())
+	if m.showCancelMsg {
+		b.WriteString("\n" + common.FontColor("Press Ctrl+C again to exit", "yellow"))
+	}
+	return b.String()
+}
+
+// DangerConfirm asks the user to type phrase exactly (e.g. a package name) before a
+// destructive action. It resolves "true" only on an exact match and "false" on Esc.
+func DangerConfirm(promptText, headerText, footerText, phrase, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	return dangerConfirm(newPromptSession(), promptText, headerText, footerText, phrase, backKey, timeout, resetTimeoutOnKey)
+}
+
+func dangerConfirm(s *promptSession, promptText, headerText, footerText, phrase, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	o := DangerConfirmOptions{
+		PromptOptions: PromptOptions{
+			Header:            headerText,
+			Footer:            footerText,
+			BackKey:           backKey,
+			Timeout:           time.Duration(timeout) * time.Second,
+			ResetTimeoutOnKey: resetTimeoutOnKey,
+		},
+		Prompt: promptText,
+		Phrase: phrase,
+	}
+	confirmed, err := o.run(s)
+	answer := ""
+	if err == nil || err == ErrBack {
+		answer = strconv.FormatBool(confirmed)
+	}
+	result, _ := json.Marshal(&ConfirmResult{
+		Confirmed:  answer,
+		resultMeta: s.meta(err),
+	})
+	return string(result)
+}
+
+func (o DangerConfirmOptions) run(s *promptSession) (bool, error) {
+	const minTerminalHeight = 7
+
+	if o.Phrase == "" {
+		return false, &PromptError{Code: CodeInvalidInput, Message: "confirmation phrase must not be empty"}
+	}
+
+	m := &dangerConfirmModel{
+		phrase:     []rune(o.Phrase),
+		promptText: o.Prompt,
+		headerText: o.Header,
+		footerText: o.Footer,
+		backKey:    o.BackKey,
+		timeout:    newPromptTimeout(o.timeoutSeconds(), o.ResetTimeoutOnKey),
+	}
+
+	if err := s.run(m, minTerminalHeight); err != nil {
+		return false, err
+	}
+	if s.cancelled() {
+		return false, ErrCancelled
+	}
+	return m.outcome()
+}
+
+// outcome is the answer of the confirmation once its program has quit.
+func (m *dangerConfirmModel) outcome() (bool, error) {
+	if m.timeout.expired {
+		// There is no safe default for a destructive action
+		return false, ErrTimeout
+	}
+	if m.wentBack {
+		return false, ErrBack
+	}
+	if m.canceled {
+		return false, ErrCancelled
+	}
+	return m.confirmed, nil
+}
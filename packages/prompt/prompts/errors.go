@@ -0,0 +1,96 @@
+package prompts
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error codes reported in the "code" field of every result. They are a stable contract:
+// codes are never renamed or reused, new codes may be added. Besides the code a failed
+// result carries:
+//
+//   - "message": a human readable description, not meant to be matched
+//   - "details": optional specifics such as the underlying error, omitted when empty
+//   - "error": the legacy text, "message: details" (kept for existing callers)
+//
+// All four fields are empty or omitted when the prompt succeeded.
+const (
+	// CodeCancelled: the user pressed Ctrl+C twice, or the prompt was cancelled from outside ("reason" tells why)
+	CodeCancelled = "CANCELLED"
+	// CodeBack: the user pressed the back key; the result still carries the partial value
+	CodeBack = "BACK"
+	// CodeTimeout: the timeout expired and the prompt had no default to resolve with
+	CodeTimeout = "TIMEOUT"
+	// CodeBusy: another prompt owns the terminal and the concurrency policy is "reject"
+	CodeBusy = "BUSY"
+	// CodeInvalidInputJSON: a JSON argument (items, schema, ...) could not be parsed
+	CodeInvalidInputJSON = "INVALID_INPUT_JSON"
+	// CodeInvalidInput: an argument was well-formed but not usable, e.g. an empty list
+	CodeInvalidInput = "INVALID_INPUT"
+	// CodeNoTTY: no terminal is available to render the prompt on
+	CodeNoTTY = "NO_TTY"
+	// CodeTerminalTooSmall: the terminal is too small and could not be resized
+	CodeTerminalTooSmall = "TERMINAL_TOO_SMALL"
+	// CodeRenderFailed: the prompt could not be rendered or failed while running
+	CodeRenderFailed = "RENDER_FAILED"
+	// CodeInvalidHandle: an async prompt handle does not exist or was already collected
+	CodeInvalidHandle = "INVALID_HANDLE"
+)
+
+// PromptError is the error returned by the typed API and reported in result payloads.
+type PromptError struct {
+	Code    string
+	Message string
+	Details string
+}
+
+func (e *PromptError) Error() string {
+	if e.Details == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Message, e.Details)
+}
+
+// Errors returned by the typed prompt functions. The string based functions report the
+// same text in the "error" field of their result, so both APIs stay interchangeable.
+var (
+	// ErrCancelled is returned when the user pressed Ctrl+C twice or the prompt was cancelled from outside
+	ErrCancelled = &PromptError{Code: CodeCancelled, Message: "Cancelled"}
+	// ErrBack is returned when the user pressed the back key; the partial value is returned alongside
+	ErrBack = &PromptError{Code: CodeBack, Message: backError}
+	// ErrTimeout is returned when the timeout expired and the prompt has no default to resolve with
+	ErrTimeout = &PromptError{Code: CodeTimeout, Message: timeoutError}
+	// ErrBusy is returned when another prompt owns the terminal and the concurrency policy is "reject"
+	ErrBusy = &PromptError{Code: CodeBusy, Message: "busy"}
+)
+
+func newPromptError(code, message string, details error) *PromptError {
+	e := &PromptError{Code: code, Message: message}
+	if details != nil {
+		e.Details = details.Error()
+	}
+	return e
+}
+
+// terminalSizeError is reported when the size of the terminal cannot be read.
+func terminalSizeError(err error) error {
+	return newPromptError(CodeNoTTY, "failed to get terminal size", err)
+}
+
+// terminalResizeError is reported when waiting for a large enough terminal failed.
+func terminalResizeError(err error) error {
+	return newPromptError(CodeTerminalTooSmall, "failed to wait for terminal resize", err)
+}
+
+// errorFields returns the error, code, message and details fields of a result for err.
+// Errors that are not a PromptError come from the program and are reported as RENDER_FAILED.
+func errorFields(err error) (text, code, message, details string) {
+	if err == nil {
+		return "", "", "", ""
+	}
+	var perr *PromptError
+	if errors.As(err, &perr) {
+		return err.Error(), perr.Code, perr.Message, perr.Details
+	}
+	return err.Error(), CodeRenderFailed, err.Error(), ""
+}
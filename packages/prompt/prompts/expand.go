@@ -0,0 +1,279 @@
+package prompts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mritd/bubbles/common"
+
+	"github.com/mritd/bubbles/selector"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// expandHelpKey is reserved for toggling the full option list.
+const expandHelpKey = "?"
+
+// ExpandItem is a single option of an Expand prompt, chosen by pressing Key.
+type ExpandItem struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+type ExpandResult struct {
+	Value string `json:"value"`
+	resultMeta
+}
+
+type expandModel struct {
+	items            []ExpandItem
+	promptText       string
+	footerText       string
+	defaultIndex     int
+	expanded         bool
+	unknownKey       string
+	chosen           int
+	ctrlCPressedOnce bool
+	ctrlCPressTime   time.Time
+	showCancelMsg    bool
+	canceled         bool
+	backKey          string
+	wentBack         bool
+	timeout          promptTimeout
+}
+
+func (m *expandModel) Init() tea.Cmd {
+	return m.timeout.init()
+}
+
+type expandResetCancelMsg struct{}
+
+func (m *expandModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if handled, cmd := m.timeout.update(msg); handled {
+		return m, cmd
+	}
+
+	// Handle double Ctrl+C first
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "ctrl+c" {
+		now := time.Now()
+		if m.ctrlCPressedOnce && now.Sub(m.ctrlCPressTime) < 2*time.Second {
+			// Second Ctrl+C within 2 seconds - actually cancel
+			m.canceled = true
+			return m, tea.Quit
+		}
+		// First Ctrl+C - show message and set timer
+		m.ctrlCPressedOnce = true
+		m.ctrlCPressTime = now
+		m.showCancelMsg = true
+		// Reset after 2 seconds
+		return m, tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
+			return expandResetCancelMsg{}
+		})
+	}
+
+	// Handle reset message
+	if _, ok := msg.(expandResetCancelMsg); ok {
+		// Reset cancel state after timeout
+		m.ctrlCPressedOnce = false
+		m.showCancelMsg = false
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if isBackKey(keyMsg, m.backKey) {
+		m.wentBack = true
+		return m, tea.Quit
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyEnter:
+		// Enter picks the default option, if any
+		if m.defaultIndex >= 0 {
+			m.chosen = m.defaultIndex
+			return m, tea.Quit
+		}
+		return m, nil
+	case tea.KeyEsc:
+		m.expanded = false
+		m.unknownKey = ""
+		return m, nil
+	case tea.KeyRunes:
+		key := string(keyMsg.Runes)
+		if key == expandHelpKey {
+			m.expanded = !m.expanded
+			m.unknownKey = ""
+			return m, nil
+		}
+		for i, item := range m.items {
+			if strings.EqualFold(item.Key, key) {
+				m.chosen = i
+				return m, tea.Quit
+			}
+		}
+		m.unknownKey = key
+	}
+	return m, nil
+}
+
+// keyHint renders the compact key list, e.g. "Ynadq?" with the default key upper-cased.
+func (m *expandModel) keyHint() string {
+	var b strings.Builder
+	for i, item := range m.items {
+		if i == m.defaultIndex {
+			b.WriteString(strings.ToUpper(item.Key))
+		} else {
+			b.WriteString(strings.ToLower(item.Key))
+		}
+	}
+	b.WriteString(expandHelpKey)
+	return b.String()
+}
+
+func (m *expandModel) View() string {
+	var b strings.Builder
+	hint := common.FontColor(fmt.Sprintf("(%s)", m.keyHint()), selector.ColorUnSelected)
+
+	if m.expanded {
+		b.WriteString(common.FontColor(m.promptText, selector.ColorHeader) + "\n")
+		for i, item := range m.items {
+			line := fmt.Sprintf("  %s - %s", strings.ToLower(item.Key), item.Label)
+			color := selector.ColorUnSelected
+			if i == m.defaultIndex {
+				line += " (default)"
+				color = selector.ColorSelected
+			}
+			b.WriteString(common.FontColor(line, color) + "\n")
+		}
+		b.WriteString(common.FontColor(fmt.Sprintf("  %s - Print help", expandHelpKey), selector.ColorUnSelected) + "\n")
+		b.WriteString(fmt.Sprintf("%s %s ", common.FontColor("Answer", selector.ColorHeader), hint))
+	} else {
+		b.WriteString(fmt.Sprintf("%s %s ", common.FontColor(m.promptText, selector.ColorHeader), hint))
+	}
+
+	if m.unknownKey != "" {
+		b.WriteString("\n" + common.FontColor(fmt.Sprintf("Unknown key %q, press %s for help", m.unknownKey, expandHelpKey), "1"))
+	}
+	if m.footerText != "" {
+		b.WriteString("\n" + common.FontColor(m.footerText, selector.ColorFooter))
+	}
+	b.WriteString(m.timeout.view())
+	if m.showCancelMsg {
+		b.WriteString("\n" + common.FontColor("Press Ctrl+C again to exit", "yellow"))
+	}
+	return b.String()
+}
+
+// Expand is a git-add-p style prompt: every option has a single-letter key that
+// resolves immediately, "?" expands the list with descriptions and Enter picks defaultKey.
+func Expand(jsonData, promptText, footerText, defaultKey, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	return expand(newPromptSession(), jsonData, promptText, footerText, defaultKey, backKey, timeout, resetTimeoutOnKey)
+}
+
+func expand(s *promptSession, jsonData, promptText, footerText, defaultKey, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	var items []ExpandItem
+	if err := parseJSONArg(jsonData, "expand options", &items); err != nil {
+		result, _ := json.Marshal(&ExpandResult{
+			Value:      "",
+			resultMeta: s.meta(err),
+		})
+		return string(result)
+	}
+	o := ExpandOptions{
+		PromptOptions: PromptOptions{
+			Footer:            footerText,
+			BackKey:           backKey,
+			Timeout:           time.Duration(timeout) * time.Second,
+			ResetTimeoutOnKey: resetTimeoutOnKey,
+		},
+		Prompt:  promptText,
+		Default: defaultKey,
+	}
+	value, err := o.run(s, items)
+	result, _ := json.Marshal(&ExpandResult{
+		Value:      value,
+		resultMeta: s.meta(err),
+	})
+	return string(result)
+}
+
+func (o ExpandOptions) run(s *promptSession, items []ExpandItem) (string, error) {
+	const minTerminalHeight = 5
+
+	defaultIndex, err := o.validate(items)
+	if err != nil {
+		return "", err
+	}
+
+	m := &expandModel{
+		items:        items,
+		promptText:   o.Prompt,
+		footerText:   o.Footer,
+		defaultIndex: defaultIndex,
+		chosen:       -1,
+		backKey:      o.BackKey,
+		timeout:      newPromptTimeout(o.timeoutSeconds(), o.ResetTimeoutOnKey),
+	}
+	m.timeout.hasDefault = defaultIndex >= 0
+
+	if err := s.run(m, minTerminalHeight); err != nil {
+		return "", err
+	}
+	if s.cancelled() {
+		return "", ErrCancelled
+	}
+	return m.outcome()
+}
+
+// validate checks that every option has its own single-character key and returns the
+// index of the option with the default key, -1 for none.
+func (o ExpandOptions) validate(items []ExpandItem) (int, error) {
+	if len(items) == 0 {
+		return -1, &PromptError{Code: CodeInvalidInput, Message: "invalid expand options", Details: "no options"}
+	}
+	defaultIndex := -1
+	seen := make(map[string]bool)
+	for i, item := range items {
+		key := strings.ToLower(item.Key)
+		errText := ""
+		switch {
+		case len([]rune(key)) != 1:
+			errText = fmt.Sprintf("key %q of %q must be a single character", item.Key, item.Value)
+		case key == expandHelpKey:
+			errText = fmt.Sprintf("key %q is reserved for help", expandHelpKey)
+		case seen[key]:
+			errText = fmt.Sprintf("duplicate key %q", item.Key)
+		}
+		if errText != "" {
+			return -1, &PromptError{Code: CodeInvalidInput, Message: "invalid expand options", Details: errText}
+		}
+		seen[key] = true
+		if o.Default != "" && strings.EqualFold(item.Key, o.Default) {
+			defaultIndex = i
+		}
+	}
+	return defaultIndex, nil
+}
+
+// outcome is the answer of the expand prompt once its program has quit.
+func (m *expandModel) outcome() (string, error) {
+	if m.timeout.expired {
+		if m.defaultIndex < 0 {
+			return "", ErrTimeout
+		}
+		return m.items[m.defaultIndex].Value, nil
+	}
+	if m.wentBack {
+		return "", ErrBack
+	}
+	if m.canceled || m.chosen < 0 {
+		return "", ErrCancelled
+	}
+	return m.items[m.chosen].Value, nil
+}
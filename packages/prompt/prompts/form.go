@@ -0,0 +1,747 @@
+package prompts
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/mritd/bubbles/common"
+
+	"github.com/mritd/bubbles/selector"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	formFieldInput       = "input"
+	formFieldSelect      = "select"
+	formFieldMultiselect = "multiselect"
+	formFieldConfirm     = "confirm"
+	formFieldNumber      = "number"
+
+	// formOptionsPerPage limits how many options of a focused select/multiselect field are shown at once
+	formOptionsPerPage = 7
+)
+
+// FormField describes a single question of a Form.
+//
+// Min/Max are interpreted per type: the value range for "number", the text length for
+// "input" and the number of selected options for "multiselect".
+type FormField struct {
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Label       string          `json:"label"`
+	Hint        string          `json:"hint"`
+	Placeholder string          `json:"placeholder"`
+	Options     []ListItem      `json:"options"`
+	Default     json.RawMessage `json:"default"`
+	Required    bool            `json:"required"`
+	EchoMode    string          `json:"echoMode"`
+	CharLimit   int             `json:"charLimit"`
+	Pattern     string          `json:"pattern"`
+	Min         *float64        `json:"min"`
+	Max         *float64        `json:"max"`
+	When        *FormCondition  `json:"when"`
+}
+
+// FormCondition makes a field visible only when an earlier field matches.
+// Equals/NotEquals compare against the earlier value; for multiselect fields a
+// scalar operand checks whether the value is among the selected ones.
+// In matches when the earlier value equals any of the listed values.
+type FormCondition struct {
+	Field     string            `json:"field"`
+	Equals    json.RawMessage   `json:"equals"`
+	NotEquals json.RawMessage   `json:"notEquals"`
+	In        []json.RawMessage `json:"in"`
+}
+
+type FormResult struct {
+	Values map[string]interface{} `json:"values"`
+	resultMeta
+}
+
+type formFieldState struct {
+	input    textinput.Model
+	pattern  *regexp.Regexp
+	cursor   int
+	selected map[int]bool
+	checked  bool
+	err      string
+}
+
+type formModel struct {
+	fields           []FormField
+	states           []*formFieldState
+	focus            int
+	headerText       string
+	footerText       string
+	ctrlCPressedOnce bool
+	ctrlCPressTime   time.Time
+	showCancelMsg    bool
+	canceled         bool
+	submitted        bool
+	backKey          string
+	wentBack         bool
+	timeout          promptTimeout
+}
+
+func (m *formModel) Init() tea.Cmd {
+	return m.timeout.init()
+}
+
+type formResetCancelMsg struct{}
+
+func (m *formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if handled, cmd := m.timeout.update(msg); handled {
+		return m, cmd
+	}
+
+	// Handle double Ctrl+C first - must intercept before fields see it
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "ctrl+c" {
+		now := time.Now()
+		if m.ctrlCPressedOnce && now.Sub(m.ctrlCPressTime) < 2*time.Second {
+			// Second Ctrl+C within 2 seconds - actually cancel
+			m.canceled = true
+			return m, tea.Quit
+		}
+		// First Ctrl+C - show message and set timer
+		m.ctrlCPressedOnce = true
+		m.ctrlCPressTime = now
+		m.showCancelMsg = true
+		// Reset after 2 seconds
+		return m, tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
+			return formResetCancelMsg{}
+		})
+	}
+
+	// Handle reset message
+	if _, ok := msg.(formResetCancelMsg); ok {
+		// Reset cancel state after timeout
+		m.ctrlCPressedOnce = false
+		m.showCancelMsg = false
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	// Shift+Tab moves between fields, so it only acts as the back key on the first field
+	if isBackKey(keyMsg, m.backKey) && (keyMsg.Type != tea.KeyShiftTab || m.nextVisible(m.focus, -1) < 0) {
+		m.wentBack = true
+		return m, tea.Quit
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyTab:
+		m.advance()
+		return m, nil
+	case tea.KeyShiftTab:
+		if prev := m.nextVisible(m.focus, -1); prev >= 0 {
+			m.focusField(prev)
+		}
+		return m, nil
+	case tea.KeyEnter:
+		if m.nextVisible(m.focus, 1) >= 0 {
+			m.advance()
+			return m, nil
+		}
+		// Enter on the last visible field submits the whole form
+		for i := range m.fields {
+			if !m.visible(i) {
+				continue
+			}
+			if m.validateField(i) != "" {
+				m.focusField(i)
+				return m, nil
+			}
+		}
+		m.submitted = true
+		return m, tea.Quit
+	}
+
+	return m, m.updateField(keyMsg)
+}
+
+// advance validates the focused field and moves to the next visible one.
+// It returns false when the focused field is invalid.
+func (m *formModel) advance() bool {
+	if m.validateField(m.focus) != "" {
+		return false
+	}
+	if next := m.nextVisible(m.focus, 1); next >= 0 {
+		m.focusField(next)
+	}
+	return true
+}
+
+func (m *formModel) updateField(msg tea.KeyMsg) tea.Cmd {
+	field := m.fields[m.focus]
+	state := m.states[m.focus]
+
+	switch field.Type {
+	case formFieldInput, formFieldNumber:
+		// Convert space key to rune message, textinput only handles runes
+		if msg.Type == tea.KeySpace {
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}}
+		}
+		if field.Type == formFieldNumber && msg.Type == tea.KeyRunes && !isNumberInput(msg.Runes) {
+			return nil
+		}
+		var cmd tea.Cmd
+		before := state.input.Value()
+		state.input, cmd = state.input.Update(msg)
+		if state.input.Value() != before {
+			state.err = ""
+		}
+		return cmd
+	case formFieldSelect, formFieldMultiselect:
+		switch msg.String() {
+		case "up", "k":
+			state.cursor = m.stepOption(field, state.cursor, -1)
+		case "down", "j":
+			state.cursor = m.stepOption(field, state.cursor, 1)
+		case " ":
+			if field.Type == formFieldMultiselect && state.cursor < len(field.Options) && !field.Options[state.cursor].Disabled {
+				if state.selected[state.cursor] {
+					delete(state.selected, state.cursor)
+				} else {
+					state.selected[state.cursor] = true
+				}
+				state.err = ""
+			}
+		}
+	case formFieldConfirm:
+		switch msg.String() {
+		case "left", "right", "h", "l", " ":
+			state.checked = !state.checked
+		case "y", "Y":
+			state.checked = true
+		case "n", "N":
+			state.checked = false
+		}
+	}
+	return nil
+}
+
+// stepOption moves the option cursor in direction, skipping disabled options.
+func (m *formModel) stepOption(field FormField, cursor, direction int) int {
+	for next := cursor + direction; next >= 0 && next < len(field.Options); next += direction {
+		if !field.Options[next].Disabled {
+			return next
+		}
+	}
+	return cursor
+}
+
+func (m *formModel) focusField(i int) {
+	if m.focus >= 0 && m.focus < len(m.states) && usesTextInput(m.fields[m.focus]) {
+		m.states[m.focus].input.Blur()
+	}
+	m.focus = i
+	if usesTextInput(m.fields[i]) {
+		m.states[i].input.Focus()
+	}
+}
+
+func usesTextInput(field FormField) bool {
+	return field.Type == formFieldInput || field.Type == formFieldNumber
+}
+
+// nextVisible returns the index of the next visible field from `from` in direction,
+// or -1 when there is none.
+func (m *formModel) nextVisible(from, direction int) int {
+	for i := from + direction; i >= 0 && i < len(m.fields); i += direction {
+		if m.visible(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// visible evaluates the field's condition against the answers of earlier visible fields.
+func (m *formModel) visible(i int) bool {
+	cond := m.fields[i].When
+	if cond == nil {
+		return true
+	}
+	for j := 0; j < i; j++ {
+		if m.fields[j].Name != cond.Field {
+			continue
+		}
+		if !m.visible(j) {
+			return false
+		}
+		return cond.matches(m.value(j))
+	}
+	return false
+}
+
+func (c *FormCondition) matches(value interface{}) bool {
+	if len(c.Equals) > 0 {
+		return formValueMatches(value, c.Equals)
+	}
+	if len(c.NotEquals) > 0 {
+		return !formValueMatches(value, c.NotEquals)
+	}
+	if len(c.In) > 0 {
+		for _, candidate := range c.In {
+			if formValueMatches(value, candidate) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func formValueMatches(value interface{}, raw json.RawMessage) bool {
+	var operand interface{}
+	if err := json.Unmarshal(raw, &operand); err != nil {
+		return false
+	}
+	if values, ok := value.([]string); ok {
+		if s, ok := operand.(string); ok {
+			for _, v := range values {
+				if v == s {
+					return true
+				}
+			}
+			return false
+		}
+		list := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			list = append(list, v)
+		}
+		return reflect.DeepEqual(list, operand)
+	}
+	return reflect.DeepEqual(value, operand)
+}
+
+// value returns the current answer of a field in its JSON result representation.
+func (m *formModel) value(i int) interface{} {
+	field := m.fields[i]
+	state := m.states[i]
+	switch field.Type {
+	case formFieldNumber:
+		n, err := strconv.ParseFloat(strings.TrimSpace(state.input.Value()), 64)
+		if err != nil {
+			return nil
+		}
+		return n
+	case formFieldSelect:
+		if state.cursor < len(field.Options) && !field.Options[state.cursor].Disabled {
+			return field.Options[state.cursor].Value
+		}
+		return ""
+	case formFieldMultiselect:
+		values := []string{}
+		for idx, opt := range field.Options {
+			if state.selected[idx] && !opt.Disabled {
+				values = append(values, opt.Value)
+			}
+		}
+		return values
+	case formFieldConfirm:
+		return state.checked
+	default:
+		return state.input.Value()
+	}
+}
+
+func (m *formModel) values() map[string]interface{} {
+	values := make(map[string]interface{})
+	for i, field := range m.fields {
+		if m.visible(i) {
+			values[field.Name] = m.value(i)
+		}
+	}
+	return values
+}
+
+// validateField stores and returns the validation error of a field ("" when valid).
+func (m *formModel) validateField(i int) string {
+	field := m.fields[i]
+	state := m.states[i]
+	state.err = ""
+
+	switch field.Type {
+	case formFieldInput:
+		text := state.input.Value()
+		if field.Required && strings.TrimSpace(text) == "" {
+			state.err = "This field is required"
+		} else if text != "" && state.pattern != nil && !state.pattern.MatchString(text) {
+			state.err = fmt.Sprintf("Must match %s", field.Pattern)
+		} else if text != "" {
+			state.err = formRangeError(float64(len([]rune(text))), field.Min, field.Max, "at least %g characters", "at most %g characters")
+		}
+	case formFieldNumber:
+		text := strings.TrimSpace(state.input.Value())
+		if text == "" {
+			if field.Required {
+				state.err = "This field is required"
+			}
+			break
+		}
+		n, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			state.err = "Must be a number"
+			break
+		}
+		state.err = formRangeError(n, field.Min, field.Max, "must be >= %g", "must be <= %g")
+	case formFieldSelect:
+		if field.Required && m.value(i) == "" {
+			state.err = "Select an option"
+		}
+	case formFieldMultiselect:
+		count := float64(len(m.value(i).([]string)))
+		if field.Required && count == 0 {
+			state.err = "Select at least one option"
+		} else {
+			state.err = formRangeError(count, field.Min, field.Max, "select at least %g", "select at most %g")
+		}
+	}
+	return state.err
+}
+
+func formRangeError(n float64, min, max *float64, minFormat, maxFormat string) string {
+	if min != nil && n < *min {
+		return capitalize(fmt.Sprintf(minFormat, *min))
+	}
+	if max != nil && n > *max {
+		return capitalize(fmt.Sprintf(maxFormat, *max))
+	}
+	return ""
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func isNumberInput(runes []rune) bool {
+	for _, r := range runes {
+		if !strings.ContainsRune("0123456789.-+eE", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *formModel) View() string {
+	var b strings.Builder
+	if m.headerText != "" {
+		b.WriteString(common.FontColor(m.headerText, selector.ColorHeader))
+		b.WriteString("\n\n")
+	}
+
+	for i, field := range m.fields {
+		if !m.visible(i) {
+			continue
+		}
+		state := m.states[i]
+		focused := i == m.focus
+
+		marker := common.FontColor("?", selector.ColorUnSelected)
+		labelColor := selector.ColorUnSelected
+		if focused {
+			marker = common.FontColor(selector.DefaultCursor, selector.ColorCursor)
+			labelColor = selector.ColorSelected
+		} else if state.err != "" {
+			marker = common.FontColor("✘", "1")
+		}
+		label := field.Label
+		if label == "" {
+			label = field.Name
+		}
+		if field.Hint != "" {
+			label = fmt.Sprintf("%s (%s)", label, field.Hint)
+		}
+		b.WriteString(fmt.Sprintf("%s %s\n", marker, common.FontColor(label, labelColor)))
+		b.WriteString(m.fieldView(i, focused))
+		if state.err != "" {
+			b.WriteString("  " + common.FontColor("✘ "+state.err, "1") + "\n")
+		}
+	}
+
+	footer := m.footerText
+	if footer == "" {
+		footer = "Tab/Shift+Tab: move between fields, Enter: next/submit"
+	}
+	b.WriteString("\n" + common.FontColor(footer, selector.ColorFooter))
+	b.WriteString(m.timeout.view())
+	if m.showCancelMsg {
+		b.WriteString("\n" + common.FontColor("Press Ctrl+C again to exit", "yellow"))
+	}
+	return b.String()
+}
+
+func (m *formModel) fieldView(i int, focused bool) string {
+	field := m.fields[i]
+	state := m.states[i]
+
+	switch field.Type {
+	case formFieldSelect, formFieldMultiselect:
+		if !focused {
+			labels := []string{}
+			for idx, opt := range field.Options {
+				chosen := state.selected[idx]
+				if field.Type == formFieldSelect {
+					chosen = idx == state.cursor
+				}
+				if chosen && !opt.Disabled {
+					labels = append(labels, opt.Label)
+				}
+			}
+			return "  " + common.FontColor(strings.Join(labels, ", "), selector.ColorUnSelected) + "\n"
+		}
+		start := 0
+		if state.cursor >= formOptionsPerPage {
+			start = state.cursor - formOptionsPerPage + 1
+		}
+		end := start + formOptionsPerPage
+		if end > len(field.Options) {
+			end = len(field.Options)
+		}
+		var b strings.Builder
+		for idx := start; idx < end; idx++ {
+			opt := field.Options[idx]
+			prefix := "  "
+			if field.Type == formFieldMultiselect {
+				prefix = "  [ ] "
+				if state.selected[idx] {
+					prefix = "  [✓] "
+				}
+			}
+			text := opt.Label
+			if opt.Hint != "" {
+				text = fmt.Sprintf("%s (%s)", text, opt.Hint)
+			}
+			switch {
+			case opt.Disabled:
+				b.WriteString("  " + common.FontColor(prefix+text+" (disabled)", "240") + "\n")
+			case idx == state.cursor:
+				b.WriteString(common.FontColor(selector.DefaultCursor, selector.ColorCursor) + " " + common.FontColor(prefix+text, selector.ColorSelected) + "\n")
+			default:
+				b.WriteString("  " + common.FontColor(prefix+text, selector.ColorUnSelected) + "\n")
+			}
+		}
+		return b.String()
+	case formFieldConfirm:
+		yes, no := common.FontColor("Yes", selector.ColorUnSelected), common.FontColor("No", selector.ColorUnSelected)
+		activeColor := selector.ColorUnSelected
+		if focused {
+			activeColor = selector.ColorSelected
+		}
+		if state.checked {
+			yes = common.FontColor("[Yes]", activeColor)
+		} else {
+			no = common.FontColor("[No]", activeColor)
+		}
+		return fmt.Sprintf("  %s / %s\n", yes, no)
+	default:
+		if focused {
+			return "  " + state.input.View() + "\n"
+		}
+		text := state.input.Value()
+		if field.EchoMode == "password" || field.EchoMode == "none" {
+			text = common.GenMask(len([]rune(text)))
+		}
+		return "  " + common.FontColor(text, selector.ColorUnSelected) + "\n"
+	}
+}
+
+// newFormState validates a field definition and builds its initial state.
+func newFormState(field FormField) (*formFieldState, error) {
+	state := &formFieldState{selected: make(map[int]bool)}
+
+	switch field.Type {
+	case formFieldInput, formFieldNumber:
+		state.input = textinput.NewModel()
+		state.input.Prompt = ""
+		state.input.Placeholder = field.Placeholder
+		state.input.CharLimit = field.CharLimit
+		state.input.SetCursorMode(textinput.CursorStatic)
+		switch field.EchoMode {
+		case "password":
+			state.input.EchoMode = textinput.EchoPassword
+		case "none":
+			state.input.EchoMode = textinput.EchoNone
+		}
+		if field.Pattern != "" {
+			pattern, err := regexp.Compile(field.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("field %q has an invalid pattern: %s", field.Name, err)
+			}
+			state.pattern = pattern
+		}
+		if len(field.Default) > 0 {
+			var text string
+			var n float64
+			if err := json.Unmarshal(field.Default, &text); err == nil {
+				state.input.SetValue(text)
+			} else if err := json.Unmarshal(field.Default, &n); err == nil {
+				state.input.SetValue(strconv.FormatFloat(n, 'f', -1, 64))
+			}
+		}
+	case formFieldSelect, formFieldMultiselect:
+		if len(field.Options) == 0 {
+			return nil, fmt.Errorf("field %q has no options", field.Name)
+		}
+		// Start on the first enabled option
+		for state.cursor < len(field.Options)-1 && field.Options[state.cursor].Disabled {
+			state.cursor++
+		}
+		if len(field.Default) > 0 {
+			var defaults []string
+			var single string
+			if err := json.Unmarshal(field.Default, &single); err == nil {
+				defaults = []string{single}
+			} else {
+				_ = json.Unmarshal(field.Default, &defaults)
+			}
+			first := true
+			for idx, opt := range field.Options {
+				for _, d := range defaults {
+					if opt.Value != d || opt.Disabled {
+						continue
+					}
+					if first {
+						state.cursor = idx
+						first = false
+					}
+					if field.Type == formFieldMultiselect {
+						state.selected[idx] = true
+					}
+				}
+			}
+		}
+	case formFieldConfirm:
+		if len(field.Default) > 0 {
+			_ = json.Unmarshal(field.Default, &state.checked)
+		}
+	default:
+		return nil, fmt.Errorf("field %q has unknown type %q", field.Name, field.Type)
+	}
+	return state, nil
+}
+
+func Form(jsonData, headerText, footerText, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	return form(newPromptSession(), jsonData, headerText, footerText, backKey, timeout, resetTimeoutOnKey)
+}
+
+func form(s *promptSession, jsonData, headerText, footerText, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	var fields []FormField
+	if err := parseJSONArg(jsonData, "form schema", &fields); err != nil {
+		result, _ := json.Marshal(&FormResult{
+			Values:     map[string]interface{}{},
+			resultMeta: s.meta(err),
+		})
+		return string(result)
+	}
+	o := FormOptions{
+		PromptOptions: PromptOptions{
+			Header:            headerText,
+			Footer:            footerText,
+			BackKey:           backKey,
+			Timeout:           time.Duration(timeout) * time.Second,
+			ResetTimeoutOnKey: resetTimeoutOnKey,
+		},
+	}
+	values, err := o.run(s, fields)
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	result, _ := json.Marshal(&FormResult{
+		Values:     values,
+		resultMeta: s.meta(err),
+	})
+	return string(result)
+}
+
+func (o FormOptions) run(s *promptSession, fields []FormField) (map[string]interface{}, error) {
+	const minTerminalHeight = 5
+
+	states, err := newFormStates(fields)
+	if err != nil {
+		return nil, err
+	}
+	m := &formModel{
+		fields:     fields,
+		states:     states,
+		focus:      -1,
+		headerText: o.Header,
+		footerText: o.Footer,
+		backKey:    o.BackKey,
+		timeout:    newPromptTimeout(o.timeoutSeconds(), o.ResetTimeoutOnKey),
+	}
+	m.timeout.hasDefault = true
+	// The first field is always visible because it cannot depend on an earlier one
+	m.focusField(0)
+
+	if err := s.run(m, minTerminalHeight); err != nil {
+		return nil, err
+	}
+	if s.cancelled() {
+		return nil, ErrCancelled
+	}
+	return m.outcome()
+}
+
+// newFormStates checks the schema of a form and builds the state of every field.
+func newFormStates(fields []FormField) ([]*formFieldState, error) {
+	states := make([]*formFieldState, 0, len(fields))
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		errText := ""
+		switch {
+		case field.Name == "":
+			errText = "every form field needs a name"
+		case seen[field.Name]:
+			errText = fmt.Sprintf("duplicate field name %q", field.Name)
+		case field.When != nil && !seen[field.When.Field]:
+			errText = fmt.Sprintf("field %q depends on %q which is not an earlier field", field.Name, field.When.Field)
+		}
+		state, err := newFormState(field)
+		if errText == "" && err != nil {
+			errText = err.Error()
+		}
+		if errText != "" {
+			return nil, &PromptError{Code: CodeInvalidInput, Message: "invalid form schema", Details: errText}
+		}
+		seen[field.Name] = true
+		states = append(states, state)
+	}
+	if len(fields) == 0 {
+		return nil, &PromptError{Code: CodeInvalidInput, Message: "invalid form schema", Details: "no fields"}
+	}
+	return states, nil
+}
+
+// outcome is the answer of the form once its program has quit.
+func (m *formModel) outcome() (map[string]interface{}, error) {
+	if m.timeout.expired {
+		// Resolve with the current (default-initialized) answers when they are all valid
+		for i := range m.fields {
+			if m.visible(i) && m.validateField(i) != "" {
+				return nil, ErrTimeout
+			}
+		}
+		return m.values(), nil
+	}
+	if m.wentBack {
+		return m.values(), ErrBack
+	}
+	if m.canceled || !m.submitted {
+		return nil, ErrCancelled
+	}
+	return m.values(), nil
+}
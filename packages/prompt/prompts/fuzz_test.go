@@ -0,0 +1,368 @@
+package prompts
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mritd/bubbles/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fuzzKeys are the messages a fuzzed key sequence is built from, one per input byte.
+var fuzzKeys = []tea.Msg{
+	tea.KeyMsg{Type: tea.KeyUp},
+	tea.KeyMsg{Type: tea.KeyDown},
+	tea.KeyMsg{Type: tea.KeyLeft},
+	tea.KeyMsg{Type: tea.KeyRight},
+	tea.KeyMsg{Type: tea.KeyPgUp},
+	tea.KeyMsg{Type: tea.KeyPgDown},
+	tea.KeyMsg{Type: tea.KeyHome},
+	tea.KeyMsg{Type: tea.KeyEnd},
+	tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}},
+	tea.KeyMsg{Type: tea.KeyEnter},
+	tea.KeyMsg{Type: tea.KeyEsc},
+	tea.KeyMsg{Type: tea.KeyBackspace},
+	tea.KeyMsg{Type: tea.KeyTab},
+	tea.KeyMsg{Type: tea.KeyShiftTab},
+	tea.KeyMsg{Type: tea.KeyCtrlC},
+	tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}},
+	tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}},
+	tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}},
+	tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}},
+	tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}},
+	tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}},
+	tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}},
+	tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}},
+	tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}},
+	tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'5'}},
+	tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'9'}},
+	tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'é'}},
+	tea.WindowSizeMsg{Width: 80, Height: 24},
+	tea.WindowSizeMsg{Width: 1, Height: 1},
+	common.DONE,
+	resetCancelMsg{},
+	timeoutTickMsg{},
+	tea.KeyMsg{Type: tea.KeyShiftUp},
+	tea.KeyMsg{Type: tea.KeyShiftDown},
+	tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}},
+}
+
+// fuzzMaxKeys bounds the key sequences so that long inputs do not stall the fuzzer.
+const fuzzMaxKeys = 256
+
+// fuzzExpire is the input byte that expires the countdown of the model before a tick is sent.
+const fuzzExpire = 0xff
+
+func isCmd(cmd, target tea.Cmd) bool {
+	return cmd != nil && reflect.ValueOf(cmd).Pointer() == reflect.ValueOf(target).Pointer()
+}
+
+// fuzzDrive feeds the key sequence to m the way a program would, without running the
+// commands it returns: common.Done is delivered as the next message and tea.Quit ends
+// the sequence. It reports whether the model quit.
+func fuzzDrive(m tea.Model, timeout *promptTimeout, keys []byte) bool {
+	if len(keys) > fuzzMaxKeys {
+		keys = keys[:fuzzMaxKeys]
+	}
+	m.Init()
+	for _, key := range keys {
+		msg := fuzzKeys[int(key)%len(fuzzKeys)]
+		if key == fuzzExpire {
+			if timeout.enabled() {
+				timeout.deadline = time.Now().Add(-time.Second)
+			}
+			msg = timeoutTickMsg{}
+		}
+		for msg != nil {
+			_, cmd := m.Update(msg)
+			_ = m.View()
+			switch {
+			case isCmd(cmd, tea.Quit):
+				return true
+			case isCmd(cmd, common.Done):
+				msg = common.DONE
+			default:
+				msg = nil
+			}
+		}
+	}
+	return false
+}
+
+// fuzzPerPage keeps the page size small and positive, as run does for the string based API.
+func fuzzPerPage(n int) int {
+	if n < 0 {
+		n = -n
+	}
+	return n%20 + 1
+}
+
+// checkPromptError fails unless err is nil or one of the errors a prompt resolves with.
+func checkPromptError(t *testing.T, err error) {
+	t.Helper()
+	if err == nil {
+		return
+	}
+	var promptErr *PromptError
+	if !errors.As(err, &promptErr) {
+		t.Fatalf("unexpected error type %T: %v", err, err)
+	}
+}
+
+func FuzzSelection(f *testing.F) {
+	f.Add(`[{"value":"a","label":"A"},{"value":"b","label":"B"}]`, "", "", "", 0, false, []byte{1, 9})
+	f.Add(`[{"value":"a","disabled":true},{"value":"b"},{"value":"c","disabled":true}]`, "b", "a", "", 2, true, []byte{0, 0, 23, 9})
+	f.Add(`[{"value":"a"},{"value":"b"},{"value":"c"},{"value":"d"},{"value":"e"},{"value":"f"}]`, "f", "c", "", 3, false, []byte{25, 5, 4, 9})
+	f.Add(`[{"value":"x"}]`, "x", "x", "", 1, false, []byte{fuzzExpire})
+	f.Add(`[{"value":"a"},{"value":"b","disabled":true}]`, "", "", "Other…", 0, false, []byte{1, 1, 9, 19, 8, 9, 10, 9, 9})
+	f.Add(`[{"value":"a","children":[{"value":"a1","disabled":true},{"value":"a2","children":[{"value":"x"}]}]},{"value":"b"}]`, "b", "", "Other…", 0, false, []byte{9, 0, 3, 2, 10, 1, 9, 9})
+	f.Add(`[{"kind":"label","label":"Web"},{"value":"a"},{"kind":"separator"},{"value":"b","disabled":true},{"kind":"separator","label":"More"}]`, "", "x", "Other…", 2, true, []byte{0, 1, 1, 1, 0, 9})
+	f.Add(`[]`, "", "", "", 0, false, []byte{9})
+
+	f.Fuzz(func(t *testing.T, itemsJSON, defaultValue, initialValue, other string, perPage int, autocomplete bool, keys []byte) {
+		var items []ListItem
+		if err := parseJSONArg(itemsJSON, "items", &items); err != nil {
+			return
+		}
+		o := SelectOptions{PerPage: fuzzPerPage(perPage), Autocomplete: autocomplete, Default: defaultValue, Initial: initialValue, other: other}
+		o.Timeout = time.Second
+		if err := o.validate(items); err != nil {
+			return
+		}
+		m := o.newModel(items, o.PerPage)
+		fuzzDrive(m, &m.timeout, keys)
+
+		index, custom, err := m.outcome()
+		checkPromptError(t, err)
+		checkCustom(t, custom, other)
+		if custom != "" && (index != -1 || err != nil) {
+			t.Fatalf("custom value %q returned with index %d and error %v", custom, index, err)
+		}
+		if index == -1 {
+			return
+		}
+		// The index refers to the open submenu, or to the top-level menu on timeout
+		menu := m.items
+		if m.timeout.expired {
+			menu = items
+		}
+		if index < 0 || index >= len(menu) {
+			t.Fatalf("selected index %d out of range for %d items", index, len(menu))
+		}
+		if menu[index].skipped() && !errors.Is(err, ErrBack) {
+			t.Fatalf("selected disabled item %d", index)
+		}
+		if path := m.valuePath(index); len(path) == 0 || path[len(path)-1] != menu[index].Value {
+			t.Fatalf("path %q does not end with %q", path, menu[index].Value)
+		}
+	})
+}
+
+func FuzzMultiselect(f *testing.F) {
+	f.Add(`[{"value":"a"},{"value":"b"},{"value":"c","disabled":true}]`, `["a"]`, "", "", 0, false, []byte{8, 1, 8, 9})
+	f.Add(`[{"value":"a","disabled":true},{"value":"b"}]`, `["a","b"]`, "b", "", 1, true, []byte{fuzzExpire})
+	f.Add(`[{"value":"a"},{"value":"b"},{"value":"c"},{"value":"d"},{"value":"e"},{"value":"f"}]`, `[]`, "e", "", 2, false, []byte{25, 8, 5, 8, 9})
+	f.Add(`[{"value":"a"}]`, `["a"]`, "", "Other…", 0, false, []byte{1, 8, 19, 9, 8, 8, 20, 9, 9})
+	f.Add(`[{"kind":"label","label":"A"},{"value":"a"},{"kind":"separator"},{"value":"b"}]`, `["b"]`, "", "", 3, false, []byte{0, 8, 1, 8, 1, 8, 9})
+	f.Add(`[{"value":"a"},{"value":"b","disabled":true},{"kind":"separator"},{"value":"c"},{"value":"d"}]`, `["d"]`, "", "Other…", 2, false, []byte{33, 33, 8, 1, 1, 10, 34, 0, 8, 9})
+	f.Add(`[]`, `null`, "", "", 0, false, []byte{9})
+
+	f.Fuzz(func(t *testing.T, itemsJSON, preselectedJSON, initialValue, other string, perPage int, autocomplete bool, keys []byte) {
+		var items []ListItem
+		if err := parseJSONArg(itemsJSON, "items", &items); err != nil {
+			return
+		}
+		var preselected []string
+		if err := parseJSONArg(preselectedJSON, "preselectedValues", &preselected); err != nil {
+			return
+		}
+		o := MultiselectOptions{PerPage: fuzzPerPage(perPage), Autocomplete: autocomplete, Preselected: preselected, Initial: initialValue, other: other}
+		o.Timeout = time.Second
+		if err := o.validate(items); err != nil {
+			return
+		}
+		m := o.newModel(items, o.PerPage)
+		fuzzDrive(m, &m.timeout, keys)
+
+		indices, custom, err := m.outcome()
+		checkPromptError(t, err)
+		checkCustom(t, custom, other)
+		checkIndices(t, indices, len(items), func(i int) bool { return items[i].skipped() })
+	})
+}
+
+func FuzzGroupMultiselect(f *testing.F) {
+	f.Add(`[{"value":"g","label":"G","isGroupHeader":true,"groupName":"g"},{"value":"a","groupName":"g"},{"value":"b","groupName":"g"}]`, `["a"]`, "", true, 0, []byte{8, 1, 8, 9})
+	f.Add(`[{"value":"g","isGroupHeader":true,"groupName":"g"},{"value":"a","groupName":"g","disabled":true},{"value":"b"}]`, `["b"]`, "b", false, 1, []byte{fuzzExpire})
+	f.Add(`[{"value":"g","isGroupHeader":true,"groupName":"g"}]`, `[]`, "", true, 0, []byte{8, 9})
+	f.Add(`[{"value":"g","isGroupHeader":true,"groupName":"g"},{"value":"a","groupName":"g"},{"value":"h","isGroupHeader":true,"groupName":"h"},{"value":"b","groupName":"h"}]`, `[]`, "", true, 2, []byte{33, 33, 33, 8, 34, 0, 0, 8, 9})
+	f.Add(`[]`, `null`, "", false, 0, []byte{9})
+
+	f.Fuzz(func(t *testing.T, itemsJSON, preselectedJSON, initialValue string, selectableGroups bool, perPage int, keys []byte) {
+		var items []GroupListItem
+		if err := parseJSONArg(itemsJSON, "items", &items); err != nil {
+			return
+		}
+		var preselected []string
+		if err := parseJSONArg(preselectedJSON, "preselectedValues", &preselected); err != nil {
+			return
+		}
+		o := GroupMultiselectOptions{PerPage: fuzzPerPage(perPage), SelectableGroups: selectableGroups, Preselected: preselected, Initial: initialValue, GroupSpacing: 1}
+		o.Timeout = time.Second
+		if err := o.validate(items); err != nil {
+			return
+		}
+		m := o.newModel(items, o.PerPage)
+		fuzzDrive(m, &m.timeout, keys)
+
+		indices, err := m.outcome()
+		checkPromptError(t, err)
+		checkIndices(t, indices, len(items), func(i int) bool { return items[i].Disabled || items[i].IsGroupHeader })
+	})
+}
+
+func FuzzGroupSelect(f *testing.F) {
+	f.Add(`[{"value":"g","label":"G","isGroupHeader":true,"groupName":"g"},{"value":"a","groupName":"g"},{"value":"b","groupName":"g"}]`, "", "", true, 0, []byte{1, 9})
+	f.Add(`[{"value":"g","isGroupHeader":true,"groupName":"g"},{"value":"a","groupName":"g","disabled":true},{"value":"b"}]`, "b", "", false, 1, []byte{fuzzExpire})
+	f.Add(`[{"value":"g","isGroupHeader":true,"groupName":"g"},{"value":"a","groupName":"g"},{"value":"h","isGroupHeader":true,"groupName":"h"},{"value":"b","groupName":"h"},{"value":"c","groupName":"h","disabled":true}]`, "a", "b", true, 2, []byte{1, 1, 1, 0, 0, 9})
+	f.Add(`[{"value":"g","isGroupHeader":true,"groupName":"g"}]`, "", "", true, 0, []byte{9})
+
+	f.Fuzz(func(t *testing.T, itemsJSON, defaultValue, initialValue string, autocomplete bool, perPage int, keys []byte) {
+		var items []GroupListItem
+		if err := parseJSONArg(itemsJSON, "items", &items); err != nil {
+			return
+		}
+		o := GroupSelectOptions{PerPage: fuzzPerPage(perPage), Autocomplete: autocomplete, Default: defaultValue, Initial: initialValue, GroupSpacing: 1}
+		o.Timeout = time.Second
+		if err := o.validate(items); err != nil {
+			return
+		}
+		m := o.newModel(items, o.PerPage)
+		fuzzDrive(m, &m.timeout, keys)
+
+		index, err := m.outcome()
+		checkPromptError(t, err)
+		if index == -1 {
+			return
+		}
+		if index < 0 || index >= len(items) {
+			t.Fatalf("selected index %d out of range for %d items", index, len(items))
+		}
+		if items[index].skipped() {
+			t.Fatalf("selected index %d cannot be selected", index)
+		}
+	})
+}
+
+// checkCustom fails unless a custom value is only returned when an "Other…" entry was offered.
+func checkCustom(t *testing.T, custom, other string) {
+	t.Helper()
+	if custom != "" && other == "" {
+		t.Fatalf("custom value %q returned without an Other entry", custom)
+	}
+	if custom != strings.TrimSpace(custom) {
+		t.Fatalf("custom value %q is not trimmed", custom)
+	}
+}
+
+// checkIndices fails unless indices are sorted, unique, in range and none of them is excluded.
+func checkIndices(t *testing.T, indices []int, n int, excluded func(i int) bool) {
+	t.Helper()
+	for i, index := range indices {
+		if index < 0 || index >= n {
+			t.Fatalf("selected index %d out of range for %d items", index, n)
+		}
+		if i > 0 && index <= indices[i-1] {
+			t.Fatalf("selected indices %v are not sorted and unique", indices)
+		}
+		if excluded(index) {
+			t.Fatalf("selected index %d cannot be selected", index)
+		}
+	}
+}
+
+func FuzzConfirm(f *testing.F) {
+	f.Add("", "", "", []byte{9})
+	f.Add("true", "false", "Continue?", []byte{3, 9})
+	f.Add("false", "", "", []byte{fuzzExpire})
+	f.Add("maybe", "true", "", []byte{20, 21})
+
+	f.Fuzz(func(t *testing.T, defaultAnswer, initialAnswer, promptText string, keys []byte) {
+		o := ConfirmOptions{Prompt: promptText, Default: parseAnswer(defaultAnswer), Initial: parseAnswer(initialAnswer)}
+		o.Timeout = time.Second
+		m := o.newModel()
+		m.View()
+		if m.sl.Index() != m.startIndex {
+			t.Fatalf("cursor starts on %d, want %d", m.sl.Index(), m.startIndex)
+		}
+		quit := fuzzDrive(m, &m.timeout, keys)
+
+		confirmed, err := m.outcome()
+		checkPromptError(t, err)
+		if err != nil && confirmed {
+			t.Fatalf("confirmed alongside error %v", err)
+		}
+		if !quit && m.timeout.expired {
+			t.Fatal("countdown expired without quitting")
+		}
+	})
+}
+
+func FuzzInput(f *testing.F) {
+	f.Add("", "", false, 0, string(EchoNormal), []byte{19, 9})
+	f.Add("fallback", "typed", true, 3, string(EchoPassword), []byte{11, 11, 9})
+	f.Add("", "", true, 0, string(EchoNone), []byte{fuzzExpire})
+
+	f.Fuzz(func(t *testing.T, defaultValue, initialValue string, required bool, charLimit int, echoMode string, keys []byte) {
+		o := InputOptions{Default: defaultValue, Initial: initialValue, Required: required, CharLimit: charLimit % 64, EchoMode: EchoMode(echoMode)}
+		o.Timeout = time.Second
+		m := o.newModel()
+		fuzzDrive(m, &m.timeout, keys)
+
+		value, err := m.outcome()
+		checkPromptError(t, err)
+		if err == nil && m.timeout.expired && value != m.timeoutValue {
+			t.Fatalf("timed out with %q, want %q", value, m.timeoutValue)
+		}
+	})
+}
+
+func FuzzTags(f *testing.F) {
+	f.Add(`[{"value":"go"},{"value":"rust","disabled":true},{"value":"zig"}]`, `["ts"]`, 0, []byte{1, 9, 19, 9, 11, 11, 9})
+	f.Add(`[]`, `["a","b"]`, 2, []byte{2, 2, 3, 11, 10, fuzzExpire})
+	f.Add(`[{"value":"a","label":"A"}]`, `null`, 1, []byte{19, 9, 19, 9, 0, 1, 9, 9})
+
+	f.Fuzz(func(t *testing.T, suggestionsJSON, preselectedJSON string, perPage int, keys []byte) {
+		var suggestions []ListItem
+		if err := parseJSONArg(suggestionsJSON, "suggestions", &suggestions); err != nil {
+			return
+		}
+		var preselected []string
+		if err := parseJSONArg(preselectedJSON, "preselected values", &preselected); err != nil {
+			return
+		}
+		o := TagsOptions{PerPage: fuzzPerPage(perPage), Preselected: preselected}
+		o.Timeout = time.Second
+		if err := o.validate(suggestions); err != nil {
+			return
+		}
+		m := o.newModel(suggestions, o.PerPage)
+		fuzzDrive(m, &m.timeout, keys)
+		m.View()
+
+		values, err := m.outcome()
+		checkPromptError(t, err)
+		seen := make(map[string]bool, len(values))
+		for _, value := range values {
+			key := strings.ToLower(value)
+			if value == "" || seen[key] {
+				t.Fatalf("invalid tags %q", values)
+			}
+			seen[key] = true
+		}
+	})
+}
@@ -3,44 +3,48 @@ package prompts
 import (
 	"encoding/json"
 	"fmt"
-	"os"
-	"strings"
 	"time"
-	"unicode"
 
 	"github.com/mritd/bubbles/common"
 
 	"github.com/mritd/bubbles/selector"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"golang.org/x/term"
 )
 
 type groupMultiselectModel struct {
-	sl                    selector.Model
-	selected              map[int]bool
-	items                 []GroupListItem
-	headerText            string
-	footerText            string
-	canceled              bool
-	ctrlCPressedOnce      bool
-	ctrlCPressTime        time.Time
-	showCancelMsg         bool
-	autocompleteEnabled   bool
-	autocompleteBuffer    string
-	autocompleteLastInput time.Time
-	selectableGroups      bool
-	groupIndices          map[int]string   // Maps item index to group name
-	groupItemIndices      map[string][]int // Maps group name to item indices
+	sl               selector.Model
+	selected         map[int]bool
+	items            []GroupListItem
+	headerText       string
+	footerText       string
+	canceled         bool
+	ctrlCPressedOnce bool
+	ctrlCPressTime   time.Time
+	showCancelMsg    bool
+	autocomplete     autocompleteState
+	selectableGroups bool
+	groupIndices     map[int]string   // Maps item index to group name
+	groupItemIndices map[string][]int // Maps group name to item indices
+	backKey          string
+	wentBack         bool
+	timeout          promptTimeout
+	// preselected are the indices checked initially, returned on timeout
+	preselected []int
+	rng         *rangeSelection
 }
 
 func (m groupMultiselectModel) Init() tea.Cmd {
-	return nil
+	return m.timeout.init()
 }
 
 type groupMultiselectResetCancelMsg struct{}
 
 func (m *groupMultiselectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if handled, cmd := m.timeout.update(msg); handled {
+		return m, cmd
+	}
+
 	// Handle double Ctrl+C first - must intercept before selector sees it
 	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "ctrl+c" {
 		now := time.Now()
@@ -68,15 +72,26 @@ func (m *groupMultiselectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Handle back navigation before the selector or autocomplete see the key
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && isBackKey(keyMsg, m.backKey) {
+		m.wentBack = true
+		return m, tea.Quit
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if m.handleAutocompleteKey(msg) {
+		if m.autocomplete.handleKey(msg, &m.sl, m.navItems()) {
 			return m, nil
 		}
 		switch msg.String() {
 		case " ":
 			// Toggle selection on space
 			currentIndex := m.sl.Index()
+			if m.rng.spans(currentIndex) {
+				m.rng.toggle(m.selected, currentIndex, m.rangeable)
+				return m, nil
+			}
+			m.rng.clear()
 			// Prevent toggling disabled items and non-selectable group headers
 			if currentIndex < len(m.items) {
 				item := m.items[currentIndex]
@@ -123,66 +138,28 @@ func (m *groupMultiselectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case "up", "k":
 			// Move up, skipping disabled items and non-selectable group headers
-			_, cmd := m.sl.Update(msg)
-			// After update, check if we're on a disabled item or non-selectable group header and skip if needed
-			guard := 0
-			maxGuards := len(m.items) * 2
-			for guard < maxGuards && m.sl.Index() < len(m.items) {
-				item := m.items[m.sl.Index()]
-				if item.Disabled {
-					prevIndex := m.sl.Index()
-					_, cmd = m.sl.Update(tea.KeyMsg{Type: tea.KeyUp})
-					if m.sl.Index() == prevIndex {
-						// Can't move further, break to avoid infinite loop
-						break
-					}
-					guard++
-					continue
-				}
-				if item.IsGroupHeader && !m.selectableGroups {
-					prevIndex := m.sl.Index()
-					_, cmd = m.sl.Update(tea.KeyMsg{Type: tea.KeyUp})
-					if m.sl.Index() == prevIndex {
-						// Can't move further, break to avoid infinite loop
-						break
-					}
-					guard++
-					continue
-				}
-				break
-			}
-			return m, cmd
+			m.rng.moved()
+			return m, m.move(msg, -1)
 		case "down", "j":
 			// Move down, skipping disabled items and non-selectable group headers
-			_, cmd := m.sl.Update(msg)
-			// After update, check if we're on a disabled item or non-selectable group header and skip if needed
-			guard := 0
-			maxGuards := len(m.items) * 2
-			for guard < maxGuards && m.sl.Index() < len(m.items) {
-				item := m.items[m.sl.Index()]
-				if item.Disabled {
-					prevIndex := m.sl.Index()
-					_, cmd = m.sl.Update(tea.KeyMsg{Type: tea.KeyDown})
-					if m.sl.Index() == prevIndex {
-						// Can't move further, break to avoid infinite loop
-						break
-					}
-					guard++
-					continue
-				}
-				if item.IsGroupHeader && !m.selectableGroups {
-					prevIndex := m.sl.Index()
-					_, cmd = m.sl.Update(tea.KeyMsg{Type: tea.KeyDown})
-					if m.sl.Index() == prevIndex {
-						// Can't move further, break to avoid infinite loop
-						break
-					}
-					guard++
-					continue
-				}
-				break
+			m.rng.moved()
+			return m, m.move(msg, 1)
+		case "shift+up":
+			// Extend the range from the anchor
+			m.rng.extend(m.sl.Index())
+			return m, m.move(tea.KeyMsg{Type: tea.KeyUp}, -1)
+		case "shift+down":
+			m.rng.extend(m.sl.Index())
+			return m, m.move(tea.KeyMsg{Type: tea.KeyDown}, 1)
+		case "ctrl+v", "v":
+			// v only reaches here without autocomplete, which takes letters for the search
+			m.rng.toggleVisual(m.sl.Index())
+			return m, nil
+		case "esc":
+			if m.rng.active() {
+				m.rng.clear()
+				return m, nil
 			}
-			return m, cmd
 		}
 	}
 
@@ -190,8 +167,32 @@ func (m *groupMultiselectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// move passes key to the selector, then moves on in direction past disabled items and
+// non-selectable group headers.
+func (m *groupMultiselectModel) move(key tea.KeyMsg, direction int) tea.Cmd {
+	prev := m.sl.Index()
+	_, cmd := m.sl.Update(key)
+	skipDisabled(&m.sl, m.navItems(), prev, direction)
+	return cmd
+}
+
+// navItems are the items the cursor moves over; group headers are skipped unless they
+// can be selected.
+func (m *groupMultiselectModel) navItems() navList {
+	if m.selectableGroups {
+		return selectableGroupItems(m.items)
+	}
+	return groupItems(m.items)
+}
+
+// rangeable reports whether a range checks the item at idx; group headers are left out.
+func (m *groupMultiselectModel) rangeable(idx int) bool {
+	return idx < len(m.items) && !m.items[idx].Disabled && !m.items[idx].IsGroupHeader
+}
+
 func (m groupMultiselectModel) View() string {
 	view := m.sl.View()
+	view += m.timeout.view()
 	if m.showCancelMsg {
 		view += "\n" + common.FontColor("Press Ctrl+C again to exit", "yellow")
 	}
@@ -207,301 +208,156 @@ type GroupListItem struct {
 	GroupName     string `json:"groupName"`
 }
 
-type GroupMultiselectResult struct {
-	SelectedIndices []string `json:"selectedIndices"`
-	Error           string   `json:"error"`
-}
-
-type groupMultiselectWaitForResizeModel struct {
-	minHeight int
-	message   string
+// groupTree is the layout of grouped items shared by the grouped prompts.
+type groupTree struct {
+	groupIndices     map[int]string   // Maps item index to group name
+	groupItemIndices map[string][]int // Maps group name to item indices
+	// isLastInGroup marks the items drawn with └ instead of │
+	isLastInGroup map[int]bool
 }
 
-func (m groupMultiselectWaitForResizeModel) Init() tea.Cmd {
-	return tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
-		return groupMultiselectCheckTerminalSizeMsg{}
-	})
-}
-
-type groupMultiselectCheckTerminalSizeMsg struct{}
-
-func (m groupMultiselectWaitForResizeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		if msg.Height >= m.minHeight {
-			return m, tea.Quit
-		}
-		return m, tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
-			return groupMultiselectCheckTerminalSizeMsg{}
-		})
-	case groupMultiselectCheckTerminalSizeMsg:
-		fd := int(os.Stdout.Fd())
-		_, height, err := term.GetSize(fd)
-		if err == nil && height >= m.minHeight {
-			return m, tea.Quit
-		}
-		return m, tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
-			return groupMultiselectCheckTerminalSizeMsg{}
-		})
-	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" {
-			return m, tea.Quit
+func newGroupTree(items []GroupListItem) groupTree {
+	// Build group indices maps and identify last items in groups
+	groupIndices := make(map[int]string)
+	groupItemIndices := make(map[string][]int)
+	isLastInGroup := make(map[int]bool)
+	for i, item := range items {
+		if item.IsGroupHeader {
+			groupIndices[i] = item.GroupName
+			if _, ok := groupItemIndices[item.GroupName]; !ok {
+				groupItemIndices[item.GroupName] = []int{}
+			}
+		} else if item.GroupName != "" {
+			groupIndices[i] = item.GroupName
+			if _, ok := groupItemIndices[item.GroupName]; !ok {
+				groupItemIndices[item.GroupName] = []int{}
+			}
+			groupItemIndices[item.GroupName] = append(groupItemIndices[item.GroupName], i)
 		}
 	}
-	return m, nil
-}
-
-func (m groupMultiselectWaitForResizeModel) View() string {
-	fd := int(os.Stdout.Fd())
-	_, height, err := term.GetSize(fd)
-	if err != nil {
-		return fmt.Sprintf("Error checking terminal size: %s\n", err)
+	// Mark last items in each group
+	for _, itemIndices := range groupItemIndices {
+		if len(itemIndices) > 0 {
+			lastItemIndex := itemIndices[len(itemIndices)-1]
+			// Check if next item is a group header or end of list
+			if lastItemIndex+1 >= len(items) || items[lastItemIndex+1].IsGroupHeader {
+				isLastInGroup[lastItemIndex] = true
+			}
+		}
 	}
-	return fmt.Sprintf("\n%s\n\nCurrent height: %d | Required: %d\n\nPlease resize your terminal window to continue...\n", m.message, height, m.minHeight)
+	return groupTree{groupIndices: groupIndices, groupItemIndices: groupItemIndices, isLastInGroup: isLastInGroup}
 }
 
-func groupMultiselectWaitForTerminalResize(minHeight int, message string) error {
-	m := groupMultiselectWaitForResizeModel{
-		minHeight: minHeight,
-		message:   message,
-	}
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	err := p.Start()
-	if err != nil {
-		return err
-	}
-
-	// Block until terminal is large enough
-	fd := int(os.Stdout.Fd())
-	for {
-		_, height, err := term.GetSize(fd)
-		if err == nil && height >= minHeight {
-			break
-		}
-		time.Sleep(time.Second / 2)
+// bar is the tree line drawn in front of the item at idx.
+func (g groupTree) bar(idx int) string {
+	if g.isLastInGroup[idx] {
+		return "└"
 	}
-	return nil
+	return "│"
 }
 
-func (m *groupMultiselectModel) handleAutocompleteKey(msg tea.KeyMsg) bool {
-	if !m.autocompleteEnabled || len(m.items) == 0 {
-		return false
+// spacing is the tree lines drawn above the group header at idx to separate it from the
+// previous group.
+func (g groupTree) spacing(idx, lines int, color string) string {
+	if idx == 0 {
+		return ""
 	}
-	switch msg.Type {
-	case tea.KeyRunes:
-		if len(msg.Runes) == 0 {
-			return false
-		}
-		if !m.autocompleteLastInput.IsZero() && time.Since(m.autocompleteLastInput) > autocompleteResetTimeout {
-			m.autocompleteBuffer = ""
-		}
-		r := msg.Runes[0]
-		if unicode.IsSpace(r) {
-			return false
-		}
-		if unicode.IsDigit(r) && m.autocompleteBuffer == "" {
-			return false
-		}
-		if !isAutocompleteRune(r) {
-			return false
-		}
-		m.autocompleteLastInput = time.Now()
-		m.autocompleteBuffer += strings.ToLower(string(msg.Runes))
-		m.focusAutocompleteMatch()
-		return true
-	case tea.KeyBackspace:
-		if m.autocompleteBuffer == "" {
-			return false
-		}
-		m.autocompleteBuffer = trimLastRune(m.autocompleteBuffer)
-		m.autocompleteLastInput = time.Now()
-		if m.autocompleteBuffer == "" {
-			return true
-		}
-		m.focusAutocompleteMatch()
-		return true
-	case tea.KeyEsc:
-		if m.autocompleteBuffer == "" {
-			return false
-		}
-		m.autocompleteBuffer = ""
-		m.autocompleteLastInput = time.Time{}
-		return true
-	default:
-		return false
+	spacingLines := ""
+	for i := 0; i < lines; i++ {
+		spacingLines += fmt.Sprintf("\n%s", common.FontColor("│", color))
 	}
+	return spacingLines
 }
 
-func (m *groupMultiselectModel) focusAutocompleteMatch() {
-	if m.autocompleteBuffer == "" {
-		return
-	}
-	idx := m.findAutocompleteMatch()
-	if idx >= 0 {
-		m.moveSelectorTo(idx)
-	}
+type GroupMultiselectResult struct {
+	SelectedIndices []string `json:"selectedIndices"`
+	resultMeta
 }
 
-func (m *groupMultiselectModel) findAutocompleteMatch() int {
-	if m.autocompleteBuffer == "" || len(m.items) == 0 {
-		return -1
-	}
-	query := strings.ToLower(m.autocompleteBuffer)
-	total := len(m.items)
-	start := m.sl.Index()
-	for offset := 0; offset < total; offset++ {
-		idx := (start + offset) % total
-		if idx < 0 || idx >= total {
-			continue
-		}
-		item := m.items[idx]
-		if item.Disabled {
-			continue
-		}
-		// Skip group headers if they're not selectable
-		if item.IsGroupHeader && !m.selectableGroups {
-			continue
-		}
-		label := strings.ToLower(item.Label)
-		hint := strings.ToLower(item.Hint)
-		value := strings.ToLower(item.Value)
-		if strings.Contains(label, query) || (item.Hint != "" && strings.Contains(hint, query)) || strings.Contains(value, query) {
-			return idx
-		}
-	}
-	return -1
+func GroupMultiselect(jsonData, headerText, footerText string, perPage int, autocomplete, selectableGroups bool, preselectedValues, initialCursorValue string, groupSpacing int, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	return groupMultiselect(newPromptSession(), jsonData, headerText, footerText, perPage, autocomplete, selectableGroups, preselectedValues, initialCursorValue, groupSpacing, backKey, timeout, resetTimeoutOnKey)
 }
 
-func (m *groupMultiselectModel) moveSelectorTo(target int) {
-	if target < 0 || target >= len(m.items) {
-		return
+func groupMultiselect(s *promptSession, jsonData, headerText, footerText string, perPage int, autocomplete, selectableGroups bool, preselectedValues, initialCursorValue string, groupSpacing int, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	var items []GroupListItem
+	if err := parseJSONArg(jsonData, "items", &items); err != nil {
+		result, _ := json.Marshal(&GroupMultiselectResult{SelectedIndices: []string{}, resultMeta: s.meta(err)})
+		return string(result)
 	}
-	guard := 0
-	for m.sl.Index() != target && guard < len(m.items)*2 {
-		if m.sl.Index() < target {
-			if !m.stepSelector(1) {
-				break
-			}
-		} else {
-			if !m.stepSelector(-1) {
-				break
-			}
+	// Parse preselectedValues (JSON array of strings) - used for preselection
+	var preselected []string
+	if preselectedValues != "" {
+		if err := parseJSONArg(preselectedValues, "preselected values", &preselected); err != nil {
+			result, _ := json.Marshal(&GroupMultiselectResult{SelectedIndices: []string{}, resultMeta: s.meta(err)})
+			return string(result)
 		}
-		guard++
 	}
+	o := GroupMultiselectOptions{
+		PromptOptions: PromptOptions{
+			Header:            headerText,
+			Footer:            footerText,
+			BackKey:           backKey,
+			Timeout:           time.Duration(timeout) * time.Second,
+			ResetTimeoutOnKey: resetTimeoutOnKey,
+		},
+		PerPage:          perPage,
+		Autocomplete:     autocomplete,
+		SelectableGroups: selectableGroups,
+		Preselected:      preselected,
+		Initial:          initialCursorValue,
+		GroupSpacing:     groupSpacing,
+	}
+	selected, err := o.run(s, items)
+	result, _ := json.Marshal(&GroupMultiselectResult{
+		SelectedIndices: formatIndices(selected),
+		resultMeta:      s.meta(err),
+	})
+	return string(result)
 }
 
-func (m *groupMultiselectModel) stepSelector(direction int) bool {
-	key := tea.KeyMsg{Type: tea.KeyDown}
-	if direction < 0 {
-		key = tea.KeyMsg{Type: tea.KeyUp}
+func (o GroupMultiselectOptions) run(s *promptSession, items []GroupListItem) ([]int, error) {
+	if err := o.validate(items); err != nil {
+		return nil, err
 	}
-	prev := m.sl.Index()
-	m.sl.Update(key)
-	if m.sl.Index() == prev {
-		return false
+	perPage := o.PerPage
+	if perPage <= 0 {
+		perPage = 10
 	}
-	for m.sl.Index() < len(m.items) {
-		item := m.items[m.sl.Index()]
-		if item.Disabled {
-			prev = m.sl.Index()
-			m.sl.Update(key)
-			if m.sl.Index() == prev {
-				return false
-			}
-			continue
-		}
-		if item.IsGroupHeader && !m.selectableGroups {
-			prev = m.sl.Index()
-			m.sl.Update(key)
-			if m.sl.Index() == prev {
-				return false
-			}
-			continue
-		}
-		break
-	}
-	return true
-}
 
-func GroupMultiselect(jsonData, headerText, footerText string, perPage int, autocomplete, selectableGroups bool, preselectedValues, initialCursorValue string, groupSpacing int) string {
 	// Minimum height: header (1) + perPage items + footer (1) + buffer (2) = perPage + 4
 	minTerminalHeight := perPage + 4
 	if minTerminalHeight < 5 {
 		minTerminalHeight = 5
 	}
 
-	var err error
-
-	if shouldValidateTerminalSize() {
-		height, sizeErr := getTerminalHeight()
-		if sizeErr != nil {
-			result, _ := json.Marshal(&GroupMultiselectResult{
-				SelectedIndices: []string{},
-				Error:           fmt.Sprintf("failed to get terminal size: %s", sizeErr),
-			})
-			return string(result)
-		}
-
-		if height < minTerminalHeight {
-			// Wait for user to resize terminal instead of returning error
-			waitMessage := fmt.Sprintf("⚠️  Terminal height too small!\n   Current: %d lines | Required: %d lines (for perPage=%d)", height, minTerminalHeight, perPage)
-			err = groupMultiselectWaitForTerminalResize(minTerminalHeight, waitMessage)
-			if err != nil {
-				result, _ := json.Marshal(&GroupMultiselectResult{
-					SelectedIndices: []string{},
-					Error:           fmt.Sprintf("failed to wait for terminal resize: %s", err),
-				})
-				return string(result)
-			}
-		}
+	m := o.newModel(items, perPage)
+	err := s.run(m, minTerminalHeight)
+	if err != nil {
+		return nil, err
 	}
+	if s.cancelled() {
+		return nil, ErrCancelled
+	}
+	return m.outcome()
+}
+
+// newModel builds the model for items, showing perPage items at a time.
+func (o GroupMultiselectOptions) newModel(items []GroupListItem, perPage int) *groupMultiselectModel {
+	headerText, footerText := o.Header, o.Footer
+	selectableGroups, initialCursorValue, groupSpacing := o.SelectableGroups, o.Initial, o.GroupSpacing
 
-	var items []GroupListItem
-	json.Unmarshal([]byte(jsonData), &items)
 	data := []interface{}{}
 	for _, val := range items {
 		data = append(data, GroupListItem{Value: val.Value, Label: val.Label, Hint: val.Hint, Disabled: val.Disabled, IsGroupHeader: val.IsGroupHeader, GroupName: val.GroupName})
 	}
 
-	// Parse preselectedValues (JSON array of strings) - used for preselection
 	preselectedSet := make(map[string]bool)
-	if preselectedValues != "" {
-		var preselectedVals []string
-		json.Unmarshal([]byte(preselectedValues), &preselectedVals)
-		for _, val := range preselectedVals {
-			preselectedSet[val] = true
-		}
+	for _, val := range o.Preselected {
+		preselectedSet[val] = true
 	}
 
-	// Build group indices maps and identify last items in groups
-	groupIndices := make(map[int]string)
-	groupItemIndices := make(map[string][]int)
-	isLastInGroup := make(map[int]bool)
-	for i, item := range items {
-		if item.IsGroupHeader {
-			groupIndices[i] = item.GroupName
-			if _, ok := groupItemIndices[item.GroupName]; !ok {
-				groupItemIndices[item.GroupName] = []int{}
-			}
-		} else if item.GroupName != "" {
-			groupIndices[i] = item.GroupName
-			if _, ok := groupItemIndices[item.GroupName]; !ok {
-				groupItemIndices[item.GroupName] = []int{}
-			}
-			groupItemIndices[item.GroupName] = append(groupItemIndices[item.GroupName], i)
-		}
-	}
-	// Mark last items in each group
-	for _, itemIndices := range groupItemIndices {
-		if len(itemIndices) > 0 {
-			lastItemIndex := itemIndices[len(itemIndices)-1]
-			// Check if next item is a group header or end of list
-			if lastItemIndex+1 >= len(items) || items[lastItemIndex+1].IsGroupHeader {
-				isLastInGroup[lastItemIndex] = true
-			}
-		}
-	}
+	tree := newGroupTree(items)
+	groupItemIndices := tree.groupItemIndices
 
 	// Determine start index based on initialCursorValue (first preselected value or empty)
 	startIndex := 0
@@ -539,6 +395,7 @@ func GroupMultiselect(jsonData, headerText, footerText string, perPage int, auto
 		}
 	}
 
+	rng := newRangeSelection()
 	sl := selector.Model{
 		Data:    data,
 		PerPage: perPage,
@@ -566,20 +423,12 @@ func GroupMultiselect(jsonData, headerText, footerText string, perPage int, auto
 			}
 
 			// Determine if this is the last item in its group
-			isLast := isLastInGroup[gdIndex]
-			barChar := "│"
-			if isLast {
-				barChar = "└"
-			}
+			barChar := tree.bar(gdIndex)
 
 			// Add group spacing prefix for group headers (except first)
 			spacingPrefix := ""
-			if t.IsGroupHeader && groupSpacing > 0 && gdIndex > 0 {
-				spacingLines := ""
-				for i := 0; i < groupSpacing; i++ {
-					spacingLines += fmt.Sprintf("\n%s", common.FontColor("│", selector.ColorSelected))
-				}
-				spacingPrefix = spacingLines
+			if t.IsGroupHeader {
+				spacingPrefix = tree.spacing(gdIndex, groupSpacing, selector.ColorSelected)
 			}
 
 			// Group header styling
@@ -649,20 +498,12 @@ func GroupMultiselect(jsonData, headerText, footerText string, perPage int, auto
 			}
 
 			// Determine if this is the last item in its group
-			isLast := isLastInGroup[gdIndex]
-			barChar := "│"
-			if isLast {
-				barChar = "└"
-			}
+			barChar := tree.bar(gdIndex)
 
 			// Add group spacing prefix for group headers (except first)
 			spacingPrefix := ""
-			if t.IsGroupHeader && groupSpacing > 0 && gdIndex > 0 {
-				spacingLines := ""
-				for i := 0; i < groupSpacing; i++ {
-					spacingLines += fmt.Sprintf("\n%s", common.FontColor("│", selector.ColorUnSelected))
-				}
-				spacingPrefix = spacingLines
+			if t.IsGroupHeader {
+				spacingPrefix = tree.spacing(gdIndex, groupSpacing, selector.ColorUnSelected)
 			}
 
 			// Group header styling
@@ -711,6 +552,9 @@ func GroupMultiselect(jsonData, headerText, footerText string, perPage int, auto
 			if disabled {
 				return common.FontColor(fmt.Sprintf("%s %s   %d. %s (disabled)", prefix, barChar, gdIndex+1, t.Label), "240")
 			}
+			if rng.contains(gdIndex, currentCursorIndex) {
+				return common.FontColor(fmt.Sprintf("%s %s  ┃%d. %s", prefix, barChar, gdIndex+1, t.Label), selector.ColorSelected)
+			}
 			return common.FontColor(fmt.Sprintf("%s %s   %d. %s", prefix, barChar, gdIndex+1, t.Label), selector.ColorUnSelected)
 		},
 		FooterFunc: func(sl selector.Model, obj interface{}, gdIndex int) string {
@@ -726,28 +570,34 @@ func GroupMultiselect(jsonData, headerText, footerText string, perPage int, auto
 	}
 
 	m := &groupMultiselectModel{
-		ctrlCPressedOnce:    false,
-		showCancelMsg:       false,
-		selected:            selected,
-		items:               items,
-		headerText:          headerText,
-		footerText:          footerText,
-		autocompleteEnabled: autocomplete,
-		autocompleteBuffer:  "",
-		sl:                  sl,
-		selectableGroups:    selectableGroups,
-		groupIndices:        groupIndices,
-		groupItemIndices:    groupItemIndices,
-	}
+		ctrlCPressedOnce: false,
+		showCancelMsg:    false,
+		selected:         selected,
+		items:            items,
+		headerText:       headerText,
+		footerText:       footerText,
+		autocomplete:     autocompleteState{enabled: o.Autocomplete},
+		sl:               sl,
+		selectableGroups: selectableGroups,
+		groupIndices:     tree.groupIndices,
+		groupItemIndices: groupItemIndices,
+		backKey:          o.BackKey,
+		timeout:          newPromptTimeout(o.timeoutSeconds(), o.ResetTimeoutOnKey),
+		rng:              rng,
+	}
+	// On timeout, resolve with the preselected values
+	m.preselected = sortedIndices(selected, func(int) bool { return true })
+	m.timeout.hasDefault = len(m.preselected) > 0
 
 	m.sl.FooterFunc = func(sl selector.Model, obj interface{}, gdIndex int) string {
 		footer := footerText
 		if footer == "" {
-			footer = "Space: toggle, Enter: confirm"
+			footer = "Space: toggle, Shift+↑/↓: range, Enter: confirm"
 		}
-		if m.autocompleteEnabled {
-			footer = formatAutocompleteFooter(footer, m.autocompleteBuffer)
+		if m.autocomplete.enabled {
+			footer = formatAutocompleteFooter(footer, m.autocomplete.buffer)
 		}
+		footer = m.rng.footer(footer, sl.Index(), m.rangeable)
 		return common.FontColor(footer, selector.ColorFooter)
 	}
 
@@ -757,32 +607,27 @@ func GroupMultiselect(jsonData, headerText, footerText string, perPage int, auto
 		m.sl.Update(tea.KeyMsg{Type: tea.KeyDown})
 	}
 
-	p := tea.NewProgram(m)
-	err = p.Start()
-	if err != nil {
-		result, _ := json.Marshal(&GroupMultiselectResult{
-			SelectedIndices: []string{},
-			Error:           fmt.Sprintf("%s", err),
-		})
-		return string(result)
+	return m
+}
+
+// outcome is the answer of the model once its program has quit.
+func (m *groupMultiselectModel) outcome() ([]int, error) {
+	if m.timeout.expired {
+		if !m.timeout.hasDefault {
+			return nil, ErrTimeout
+		}
+		return m.preselected, nil
 	}
 	if m.canceled || m.sl.Canceled() {
-		result, _ := json.Marshal(&GroupMultiselectResult{
-			SelectedIndices: []string{},
-			Error:           "Cancelled",
-		})
-		return string(result)
+		return nil, ErrCancelled
 	}
-	indices := []string{}
-	for idx := range m.selected {
-		// Filter out disabled items and group headers from results
-		if idx < len(m.items) && !m.items[idx].Disabled && !m.items[idx].IsGroupHeader {
-			indices = append(indices, fmt.Sprintf("%d", idx))
-		}
-	}
-	result, _ := json.Marshal(&GroupMultiselectResult{
-		SelectedIndices: indices,
-		Error:           "",
+	// Filter out disabled items and group headers from results
+	indices := sortedIndices(m.selected, func(idx int) bool {
+		return idx < len(m.items) && !m.items[idx].Disabled && !m.items[idx].IsGroupHeader
 	})
-	return string(result)
+	if m.wentBack {
+		// The current selection is returned as the partial value
+		return indices, ErrBack
+	}
+	return indices, nil
 }
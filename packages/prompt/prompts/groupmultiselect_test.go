@@ -0,0 +1,44 @@
+package prompts
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestGroupMultiselectShiftRange(t *testing.T) {
+	items := []GroupListItem{
+		{Value: "web", Label: "Web", IsGroupHeader: true, GroupName: "web"},
+		{Value: "react", Label: "React", GroupName: "web"},
+		{Value: "vue", Label: "Vue", GroupName: "web", Disabled: true},
+		{Value: "svelte", Label: "Svelte", GroupName: "web"},
+		{Value: "cli", Label: "CLI", IsGroupHeader: true, GroupName: "cli"},
+		{Value: "cobra", Label: "Cobra", GroupName: "cli"},
+	}
+	// Autocomplete is on like in every binding; the range keys must not depend on it
+	m := GroupMultiselectOptions{Autocomplete: true}.newModel(items, 10)
+	press := func(keys ...tea.KeyType) {
+		for _, k := range keys {
+			m.Update(tea.KeyMsg{Type: k})
+		}
+	}
+
+	// From React down past the disabled Vue and the CLI header to Cobra
+	press(tea.KeyShiftDown, tea.KeyShiftDown, tea.KeySpace)
+	if got, _ := m.outcome(); !reflect.DeepEqual(got, []int{1, 3, 5}) {
+		t.Fatalf("checked after Shift+Down range = %v, want [1 3 5]", got)
+	}
+
+	// Shift+Up back over the same items unchecks them all, as every one is checked
+	press(tea.KeyShiftUp, tea.KeyShiftUp, tea.KeySpace)
+	if got, _ := m.outcome(); len(got) != 0 {
+		t.Fatalf("checked after Shift+Up range = %v, want none", got)
+	}
+
+	// Ctrl+V starts a visual range that the plain arrows extend
+	press(tea.KeyCtrlV, tea.KeyDown, tea.KeySpace)
+	if got, _ := m.outcome(); !reflect.DeepEqual(got, []int{1, 3}) {
+		t.Fatalf("checked after visual range = %v, want [1 3]", got)
+	}
+}
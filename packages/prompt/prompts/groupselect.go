@@ -0,0 +1,351 @@
+package prompts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mritd/bubbles/common"
+
+	"github.com/mritd/bubbles/selector"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type groupSelectModel struct {
+	sl               selector.Model
+	items            []GroupListItem
+	ctrlCPressedOnce bool
+	ctrlCPressTime   time.Time
+	showCancelMsg    bool
+	canceled         bool
+	autocomplete     autocompleteState
+	backKey          string
+	wentBack         bool
+	timeout          promptTimeout
+	// startIndex is the item the cursor started on, timeoutIndex the item returned on timeout (-1 for none)
+	startIndex   int
+	timeoutIndex int
+	defaultValue string
+}
+
+func (m groupSelectModel) Init() tea.Cmd {
+	return m.timeout.init()
+}
+
+type groupSelectResetCancelMsg struct{}
+
+func (m *groupSelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if handled, cmd := m.timeout.update(msg); handled {
+		return m, cmd
+	}
+
+	// Handle double Ctrl+C first - must intercept before selector sees it
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "ctrl+c" {
+		now := time.Now()
+		if m.ctrlCPressedOnce && now.Sub(m.ctrlCPressTime) < 2*time.Second {
+			// Second Ctrl+C within 2 seconds - actually cancel
+			m.canceled = true
+			return m, tea.Quit
+		}
+		// First Ctrl+C - show message and set timer
+		// IMPORTANT: Don't pass this to selector, return early
+		m.ctrlCPressedOnce = true
+		m.ctrlCPressTime = now
+		m.showCancelMsg = true
+		// Reset after 2 seconds
+		return m, tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
+			return groupSelectResetCancelMsg{}
+		})
+	}
+
+	// Handle reset message
+	if _, ok := msg.(groupSelectResetCancelMsg); ok {
+		// Reset cancel state after timeout
+		m.ctrlCPressedOnce = false
+		m.showCancelMsg = false
+		return m, nil
+	}
+
+	// Handle back navigation before the selector or autocomplete see the key
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && isBackKey(keyMsg, m.backKey) {
+		m.wentBack = true
+		return m, tea.Quit
+	}
+
+	// The selector returns "common.DONE" when Enter is pressed
+	switch msg {
+	case common.DONE:
+		// Group headers and disabled items can't be the answer
+		currentIndex := m.sl.Index()
+		if currentIndex < len(m.items) && m.items[currentIndex].skipped() {
+			return m, nil
+		}
+		return m, tea.Quit
+	}
+
+	// Handle navigation - skip group headers and disabled items
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.autocomplete.handleKey(msg, &m.sl, groupItems(m.items)) {
+			return m, nil
+		}
+		switch msg.String() {
+		case "up", "k":
+			prev := m.sl.Index()
+			_, cmd := m.sl.Update(msg)
+			skipDisabled(&m.sl, groupItems(m.items), prev, -1)
+			return m, cmd
+		case "down", "j":
+			prev := m.sl.Index()
+			_, cmd := m.sl.Update(msg)
+			skipDisabled(&m.sl, groupItems(m.items), prev, 1)
+			return m, cmd
+		}
+	}
+
+	_, cmd := m.sl.Update(msg)
+	return m, cmd
+}
+
+func (m groupSelectModel) View() string {
+	view := m.sl.View()
+	view += m.timeout.view()
+	if m.showCancelMsg {
+		view += "\n" + common.FontColor("Press Ctrl+C again to exit", "yellow")
+	}
+	return view
+}
+
+// skipped reports whether navigation passes over the item: it is disabled or a group header.
+func (i GroupListItem) skipped() bool {
+	return i.Disabled || i.IsGroupHeader
+}
+
+// groupOptionNumber is the 1-based number shown for the item at idx, counting the items
+// below the group headers only.
+func groupOptionNumber(items []GroupListItem, idx int) int {
+	n := 1
+	for i := 0; i < idx && i < len(items); i++ {
+		if !items[i].IsGroupHeader {
+			n++
+		}
+	}
+	return n
+}
+
+func GroupSelect(jsonData, headerText, footerText string, perPage int, autocomplete bool, defaultValue, initialValue string, groupSpacing int, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	return groupSelect(newPromptSession(), jsonData, headerText, footerText, perPage, autocomplete, defaultValue, initialValue, groupSpacing, backKey, timeout, resetTimeoutOnKey)
+}
+
+func groupSelect(s *promptSession, jsonData, headerText, footerText string, perPage int, autocomplete bool, defaultValue, initialValue string, groupSpacing int, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	var items []GroupListItem
+	if err := parseJSONArg(jsonData, "items", &items); err != nil {
+		result, _ := json.Marshal(&Result{SelectedIndex: "", resultMeta: s.meta(err)})
+		return string(result)
+	}
+	o := GroupSelectOptions{
+		PromptOptions: PromptOptions{
+			Header:            headerText,
+			Footer:            footerText,
+			BackKey:           backKey,
+			Timeout:           time.Duration(timeout) * time.Second,
+			ResetTimeoutOnKey: resetTimeoutOnKey,
+		},
+		PerPage:      perPage,
+		Autocomplete: autocomplete,
+		Default:      defaultValue,
+		Initial:      initialValue,
+		GroupSpacing: groupSpacing,
+	}
+	index, err := o.run(s, items)
+	selectedIndex := ""
+	if index >= 0 {
+		selectedIndex = strconv.Itoa(index)
+	}
+	result, _ := json.Marshal(&Result{SelectedIndex: selectedIndex, resultMeta: s.meta(err)})
+	return string(result)
+}
+
+func (o GroupSelectOptions) run(s *promptSession, items []GroupListItem) (int, error) {
+	if err := o.validate(items); err != nil {
+		return -1, err
+	}
+	perPage := o.PerPage
+	if perPage <= 0 {
+		perPage = 10
+	}
+
+	// Minimum height: header (1) + perPage items + footer (1) + buffer (2) = perPage + 4
+	minTerminalHeight := perPage + 4
+	if minTerminalHeight < 5 {
+		minTerminalHeight = 5
+	}
+
+	m := o.newModel(items, perPage)
+	err := s.run(m, minTerminalHeight)
+	if err != nil {
+		return -1, err
+	}
+	if s.cancelled() {
+		return -1, ErrCancelled
+	}
+	return m.outcome()
+}
+
+// newModel builds the model for items, showing perPage items at a time.
+func (o GroupSelectOptions) newModel(items []GroupListItem, perPage int) *groupSelectModel {
+	headerText, footerText := o.Header, o.Footer
+	defaultValue, initialValue, groupSpacing := o.Default, o.Initial, o.GroupSpacing
+
+	data := []interface{}{}
+	for _, val := range items {
+		data = append(data, val)
+	}
+
+	tree := newGroupTree(items)
+
+	// Start on initialValue, else on defaultValue, otherwise on the first item that can be
+	// selected
+	startIndex := 0
+	for i, it := range items {
+		if !it.skipped() {
+			startIndex = i
+			break
+		}
+	}
+	startValue := initialValue
+	if startValue == "" {
+		startValue = defaultValue
+	}
+	if startValue != "" {
+		for i, it := range items {
+			if it.Value == startValue && !it.skipped() {
+				startIndex = i
+				break
+			}
+		}
+	}
+
+	// On timeout, resolve with defaultValue, falling back to initialValue
+	timeoutIndex := -1
+	for _, fallback := range []string{defaultValue, initialValue} {
+		if fallback == "" || timeoutIndex >= 0 {
+			continue
+		}
+		for i, it := range items {
+			if it.Value == fallback && !it.skipped() {
+				timeoutIndex = i
+				break
+			}
+		}
+	}
+
+	// header renders a group header, highlighted while the cursor is in its group
+	header := func(t GroupListItem, gdIndex, cursor int, color string) string {
+		spacingPrefix := tree.spacing(gdIndex, groupSpacing, color)
+		if cursor >= 0 && cursor < len(items) && !items[cursor].IsGroupHeader && items[cursor].GroupName == t.GroupName {
+			color = selector.ColorSelected
+		}
+		return fmt.Sprintf("%s%s", spacingPrefix, common.FontColor(fmt.Sprintf(" ┌─ %s", t.Label), color))
+	}
+
+	sl := selector.Model{
+		Data:       data,
+		PerPage:    perPage,
+		HeaderFunc: selector.DefaultHeaderFuncWithAppend(headerText),
+		SelectedFunc: func(sl selector.Model, obj interface{}, gdIndex int) string {
+			t := obj.(GroupListItem)
+			if t.IsGroupHeader {
+				return header(t, gdIndex, sl.Index(), selector.ColorSelected)
+			}
+			barChar := tree.bar(gdIndex)
+			n := groupOptionNumber(items, gdIndex)
+			if t.Disabled {
+				if t.Hint != "" {
+					return common.FontColor(fmt.Sprintf(" %s  [%d] %s (%s) (disabled)", barChar, n, t.Label, t.Hint), "240")
+				}
+				return common.FontColor(fmt.Sprintf(" %s  [%d] %s (disabled)", barChar, n, t.Label), "240")
+			}
+			if t.Hint != "" {
+				return common.FontColor(fmt.Sprintf(" %s  [%d] %s (%s)", barChar, n, t.Label, t.Hint), selector.ColorSelected)
+			}
+			return common.FontColor(fmt.Sprintf(" %s  [%d] %s", barChar, n, t.Label), selector.ColorSelected)
+		},
+		UnSelectedFunc: func(sl selector.Model, obj interface{}, gdIndex int) string {
+			t := obj.(GroupListItem)
+			if t.IsGroupHeader {
+				return header(t, gdIndex, sl.Index(), selector.ColorUnSelected)
+			}
+			barChar := tree.bar(gdIndex)
+			n := groupOptionNumber(items, gdIndex)
+			if t.Disabled {
+				return common.FontColor(fmt.Sprintf(" %s   %d. %s (disabled)", barChar, n, t.Label), "240")
+			}
+			return common.FontColor(fmt.Sprintf(" %s   %d. %s", barChar, n, t.Label), selector.ColorUnSelected)
+		},
+		FinishedFunc: func(s interface{}) string {
+			return ""
+		},
+	}
+
+	m := &groupSelectModel{
+		sl:           sl,
+		items:        items,
+		autocomplete: autocompleteState{enabled: o.Autocomplete},
+		backKey:      o.BackKey,
+		timeout:      newPromptTimeout(o.timeoutSeconds(), o.ResetTimeoutOnKey),
+		startIndex:   startIndex,
+		timeoutIndex: timeoutIndex,
+		defaultValue: defaultValue,
+	}
+	m.timeout.hasDefault = timeoutIndex >= 0
+
+	m.sl.FooterFunc = func(sl selector.Model, obj interface{}, gdIndex int) string {
+		if !m.autocomplete.enabled {
+			return common.FontColor(footerText, selector.ColorFooter)
+		}
+		return common.FontColor(formatAutocompleteFooter(footerText, m.autocomplete.buffer), selector.ColorFooter)
+	}
+
+	// The first update only initializes the selector
+	m.sl.Update(tea.KeyMsg{Type: tea.KeyDown})
+	moveSelectorTo(&m.sl, groupItems(m.items), startIndex)
+	return m
+}
+
+// outcome is the answer of the model once its program has quit.
+func (m *groupSelectModel) outcome() (int, error) {
+	if m.timeout.expired {
+		if m.timeoutIndex < 0 {
+			return -1, ErrTimeout
+		}
+		return m.timeoutIndex, nil
+	}
+	if m.wentBack {
+		// Report the item under the cursor as the partial value
+		if idx := m.sl.Index(); idx < len(m.items) && !m.items[idx].skipped() {
+			return idx, ErrBack
+		}
+		return -1, ErrBack
+	}
+	if m.canceled || m.sl.Canceled() {
+		return -1, ErrCancelled
+	}
+	selectedIndex := m.sl.Index()
+	// Group headers and disabled items are never the answer
+	if selectedIndex < 0 || selectedIndex >= len(m.items) || m.items[selectedIndex].skipped() {
+		return -1, &PromptError{Code: CodeInvalidInput, Message: "Cannot select disabled item"}
+	}
+	// If user didn't change selection from initial position and defaultValue is provided, use it
+	if m.defaultValue != "" && selectedIndex == m.startIndex && m.items[selectedIndex].Value != m.defaultValue {
+		for i, it := range m.items {
+			if it.Value == m.defaultValue && !it.skipped() {
+				selectedIndex = i
+				break
+			}
+		}
+	}
+	return selectedIndex, nil
+}
@@ -2,15 +2,12 @@ package prompts
 
 import (
 	"encoding/json"
-	"fmt"
-	"os"
 	"time"
 
 	"github.com/mritd/bubbles/common"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/mritd/bubbles/prompt"
-	"golang.org/x/term"
 )
 
 type setDefaultValueMsg struct {
@@ -25,21 +22,30 @@ type inputModel struct {
 	ctrlCPressTime   time.Time
 	showCancelMsg    bool
 	canceled         bool
+	backKey          string
+	wentBack         bool
+	timeout          promptTimeout
+	// timeoutValue is returned on timeout, "" for none
+	timeoutValue string
 }
 
 func (m *inputModel) Init() tea.Cmd {
 	// Send initial value as a message if provided (pre-fills the input field)
 	if m.initialValue != "" {
-		return func() tea.Msg {
+		return tea.Batch(func() tea.Msg {
 			return setDefaultValueMsg{value: m.initialValue}
-		}
+		}, m.timeout.init())
 	}
-	return nil
+	return m.timeout.init()
 }
 
 type inputResetCancelMsg struct{}
 
 func (m *inputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if handled, cmd := m.timeout.update(msg); handled {
+		return m, cmd
+	}
+
 	// Handle double Ctrl+C first - must intercept before input sees it
 	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "ctrl+c" {
 		now := time.Now()
@@ -67,6 +73,12 @@ func (m *inputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Handle back navigation before the input sees the key
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && isBackKey(keyMsg, m.backKey) {
+		m.wentBack = true
+		return m, tea.Quit
+	}
+
 	// Handle default value setting
 	if setDefaultMsg, ok := msg.(setDefaultValueMsg); ok {
 		// Simulate typing the default value by sending key messages
@@ -105,6 +117,7 @@ func (m *inputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m inputModel) View() string {
 	view := m.input.View()
+	view += m.timeout.view()
 	if m.showCancelMsg {
 		view += "\n" + common.FontColor("Press Ctrl+C again to exit", "yellow")
 	}
@@ -122,165 +135,116 @@ func (m inputModel) Value() string {
 
 type InputResult struct {
 	Value string `json:"value"`
-	Error string `json:"error"`
+	resultMeta
 }
 
-type inputWaitForResizeModel struct {
-	minHeight int
-	message   string
+func Input(promptText, echoMode, validateOkPrefix, validateErrPrefix, defaultValue, initialValue string, required bool, charLimit int, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	return input(newPromptSession(), promptText, echoMode, validateOkPrefix, validateErrPrefix, defaultValue, initialValue, required, charLimit, backKey, timeout, resetTimeoutOnKey)
 }
 
-func (m inputWaitForResizeModel) Init() tea.Cmd {
-	return tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
-		return inputCheckTerminalSizeMsg{}
+func input(s *promptSession, promptText, echoMode, validateOkPrefix, validateErrPrefix, defaultValue, initialValue string, required bool, charLimit int, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	o := InputOptions{
+		PromptOptions: PromptOptions{
+			BackKey:           backKey,
+			Timeout:           time.Duration(timeout) * time.Second,
+			ResetTimeoutOnKey: resetTimeoutOnKey,
+		},
+		Prompt:            promptText,
+		EchoMode:          EchoMode(echoMode),
+		ValidateOkPrefix:  validateOkPrefix,
+		ValidateErrPrefix: validateErrPrefix,
+		Default:           defaultValue,
+		Initial:           initialValue,
+		Required:          required,
+		CharLimit:         charLimit,
+	}
+	value, err := o.run(s)
+	result, _ := json.Marshal(&InputResult{
+		Value:      value,
+		resultMeta: s.meta(err),
 	})
+	return string(result)
 }
 
-type inputCheckTerminalSizeMsg struct{}
-
-func (m inputWaitForResizeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		if msg.Height >= m.minHeight {
-			return m, tea.Quit
-		}
-		return m, tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
-			return inputCheckTerminalSizeMsg{}
-		})
-	case inputCheckTerminalSizeMsg:
-		fd := int(os.Stdout.Fd())
-		_, height, err := term.GetSize(fd)
-		if err == nil && height >= m.minHeight {
-			return m, tea.Quit
-		}
-		return m, tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
-			return inputCheckTerminalSizeMsg{}
-		})
-	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" {
-			return m, tea.Quit
-		}
-	}
-	return m, nil
-}
-
-func (m inputWaitForResizeModel) View() string {
-	fd := int(os.Stdout.Fd())
-	_, height, err := term.GetSize(fd)
-	if err != nil {
-		return fmt.Sprintf("Error checking terminal size: %s\n", err)
-	}
-	return fmt.Sprintf("\n%s\n\nCurrent height: %d | Required: %d\n\nPlease resize your terminal window to continue...\n", m.message, height, m.minHeight)
-}
+func (o InputOptions) run(s *promptSession) (string, error) {
+	const minTerminalHeight = 5
 
-func inputWaitForTerminalResize(minHeight int, message string) error {
-	m := inputWaitForResizeModel{
-		minHeight: minHeight,
-		message:   message,
-	}
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	err := p.Start()
+	m := o.newModel()
+	err := s.run(m, minTerminalHeight)
 	if err != nil {
-		return err
+		return "", err
 	}
-
-	// Block until terminal is large enough
-	fd := int(os.Stdout.Fd())
-	for {
-		_, height, err := term.GetSize(fd)
-		if err == nil && height >= minHeight {
-			break
-		}
-		time.Sleep(time.Second / 2)
+	if s.cancelled() {
+		return "", ErrCancelled
 	}
-	return nil
+	return m.outcome()
 }
 
-func Input(promptText, echoMode, validateOkPrefix, validateErrPrefix, defaultValue, initialValue string, required bool, charLimit int) string {
-	const minTerminalHeight = 5
-
-	var err error
-
-	if shouldValidateTerminalSize() {
-		height, sizeErr := getTerminalHeight()
-		if sizeErr != nil {
-			result, _ := json.Marshal(&InputResult{
-				Value: "",
-				Error: fmt.Sprintf("failed to get terminal size: %s", sizeErr),
-			})
-			return string(result)
-		}
-
-		if height < minTerminalHeight {
-			// Wait for user to resize terminal instead of returning error
-			waitMessage := fmt.Sprintf("⚠️  Terminal height too small!\n   Current: %d lines | Required: %d lines", height, minTerminalHeight)
-			err = inputWaitForTerminalResize(minTerminalHeight, waitMessage)
-			if err != nil {
-				result, _ := json.Marshal(&InputResult{
-					Value: "",
-					Error: fmt.Sprintf("failed to wait for terminal resize: %s", err),
-				})
-				return string(result)
-			}
-		}
-	}
-
-	m := inputModel{
+// newModel builds the text field model of the input.
+func (o InputOptions) newModel() *inputModel {
+	m := &inputModel{
 		ctrlCPressedOnce: false,
 		showCancelMsg:    false,
 		canceled:         false,
 		input: &prompt.Model{
 			ValidateFunc: prompt.VFNotBlank,
-			Prompt:       promptText,
-			CharLimit:    charLimit,
+			Prompt:       o.Prompt,
+			CharLimit:    o.CharLimit,
 			EchoMode:     prompt.EchoNormal,
 		},
-		defaultValue: defaultValue,
-		initialValue: initialValue,
+		defaultValue: o.Default,
+		initialValue: o.Initial,
+		backKey:      o.BackKey,
+		timeout:      newPromptTimeout(o.timeoutSeconds(), o.ResetTimeoutOnKey),
+	}
+	// On timeout, resolve with the default value, falling back to the initial value
+	timeoutValue := o.Default
+	if timeoutValue == "" {
+		timeoutValue = o.Initial
 	}
+	m.timeout.hasDefault = timeoutValue != ""
+	m.timeoutValue = timeoutValue
 
-	switch echoMode {
-	case "none":
+	switch o.EchoMode {
+	case EchoNone:
 		m.input.EchoMode = prompt.EchoNone
-	case "password":
+	case EchoPassword:
 		m.input.EchoMode = prompt.EchoPassword
 	default:
 		m.input.EchoMode = prompt.EchoNormal
 	}
 
-	if required {
+	if o.Required {
 		m.input.ValidateFunc = prompt.VFNotBlank
 	} else {
 		m.input.ValidateFunc = prompt.VFDoNothing
 	}
 
-	if validateOkPrefix != "" {
-		m.input.ValidateOkPrefix = validateOkPrefix
+	if o.ValidateOkPrefix != "" {
+		m.input.ValidateOkPrefix = o.ValidateOkPrefix
 	}
 
-	if validateErrPrefix != "" {
-		m.input.ValidateErrPrefix = validateErrPrefix
+	if o.ValidateErrPrefix != "" {
+		m.input.ValidateErrPrefix = o.ValidateErrPrefix
 	}
 
-	p := tea.NewProgram(&m)
-	err = p.Start()
-	if err != nil {
-		result, _ := json.Marshal(&InputResult{
-			Value: "",
-			Error: fmt.Sprintf("%s", err),
-		})
-		return string(result)
+	return m
+}
+
+// outcome is the answer of the input once its program has quit.
+func (m *inputModel) outcome() (string, error) {
+	if m.timeout.expired {
+		if !m.timeout.hasDefault {
+			return "", ErrTimeout
+		}
+		return m.timeoutValue, nil
+	}
+	if m.wentBack {
+		// Return the raw typed text (without defaultValue fallback) as the partial value
+		return m.input.Value(), ErrBack
 	}
 	if m.canceled {
-		result, _ := json.Marshal(&InputResult{
-			Value: "",
-			Error: "Cancelled",
-		})
-		return string(result)
+		return "", ErrCancelled
 	}
-	result, _ := json.Marshal(&InputResult{
-		Value: m.Value(),
-		Error: "",
-	})
-	return string(result)
+	return m.Value(), nil
 }
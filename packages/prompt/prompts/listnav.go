@@ -0,0 +1,227 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mritd/bubbles/selector"
+)
+
+// navList is the item list a selector cursor moves over. The cursor never stops on the
+// items skip reports, e.g. disabled items, sections and group headers.
+type navList interface {
+	len() int
+	skip(idx int) bool
+	// text is what autocomplete searches: the label, hint and value of the item
+	text(idx int) (label, hint, value string)
+}
+
+// listItems are the items of a selection or multiple selection.
+type listItems []ListItem
+
+func (l listItems) len() int          { return len(l) }
+func (l listItems) skip(idx int) bool { return l[idx].skipped() }
+func (l listItems) text(idx int) (string, string, string) {
+	return l[idx].Label, l[idx].Hint, l[idx].Value
+}
+
+// groupItems are grouped items whose group headers are skipped.
+type groupItems []GroupListItem
+
+func (l groupItems) len() int          { return len(l) }
+func (l groupItems) skip(idx int) bool { return l[idx].skipped() }
+func (l groupItems) text(idx int) (string, string, string) {
+	return l[idx].Label, l[idx].Hint, l[idx].Value
+}
+
+// selectableGroupItems are grouped items whose group headers can be selected.
+type selectableGroupItems []GroupListItem
+
+func (l selectableGroupItems) len() int          { return len(l) }
+func (l selectableGroupItems) skip(idx int) bool { return l[idx].Disabled }
+func (l selectableGroupItems) text(idx int) (string, string, string) {
+	return l[idx].Label, l[idx].Hint, l[idx].Value
+}
+
+// stepSelector moves the cursor one item in direction, on past skipped items. It reports
+// false when the cursor cannot move that way.
+func stepSelector(sl *selector.Model, items navList, direction int) bool {
+	key := tea.KeyMsg{Type: tea.KeyDown}
+	if direction < 0 {
+		key = tea.KeyMsg{Type: tea.KeyUp}
+	}
+	prev := sl.Index()
+	sl.Update(key)
+	if sl.Index() == prev {
+		return false
+	}
+	for sl.Index() < items.len() && items.skip(sl.Index()) {
+		prev = sl.Index()
+		sl.Update(key)
+		if sl.Index() == prev {
+			return false
+		}
+	}
+	return true
+}
+
+// moveSelectorTo moves the cursor to target, or as close as the skipped items allow.
+func moveSelectorTo(sl *selector.Model, items navList, target int) {
+	if target < 0 || target >= items.len() {
+		return
+	}
+	guard := 0
+	for sl.Index() != target && guard < items.len()*2 {
+		if sl.Index() < target {
+			if !stepSelector(sl, items, 1) {
+				break
+			}
+		} else {
+			if !stepSelector(sl, items, -1) {
+				break
+			}
+		}
+		guard++
+	}
+}
+
+// skipDisabled moves the cursor on past skipped items in direction after the selector
+// moved it from prev, or back to prev when only such items are left that way.
+func skipDisabled(sl *selector.Model, items navList, prev, direction int) {
+	if idx := sl.Index(); idx >= items.len() || !items.skip(idx) {
+		return
+	}
+	if !stepSelector(sl, items, direction) {
+		moveSelectorTo(sl, items, prev)
+	}
+}
+
+const autocompleteResetTimeout = 1500 * time.Millisecond
+
+// autocompleteState is the type-to-search of a selection: typed text moves the cursor to
+// the next item whose label, hint or value contains it.
+type autocompleteState struct {
+	enabled   bool
+	buffer    string
+	lastInput time.Time
+}
+
+// handleKey updates the search for msg and moves the cursor of sl over items. It reports
+// whether the key was used by the search.
+func (a *autocompleteState) handleKey(msg tea.KeyMsg, sl *selector.Model, items navList) bool {
+	if !a.enabled || items.len() == 0 {
+		return false
+	}
+	switch msg.Type {
+	case tea.KeyRunes:
+		if len(msg.Runes) == 0 {
+			return false
+		}
+		if !a.lastInput.IsZero() && time.Since(a.lastInput) > autocompleteResetTimeout {
+			a.buffer = ""
+		}
+		r := msg.Runes[0]
+		if unicode.IsSpace(r) {
+			return false
+		}
+		if unicode.IsDigit(r) && a.buffer == "" {
+			return false
+		}
+		if !isAutocompleteRune(r) {
+			return false
+		}
+		a.lastInput = time.Now()
+		a.buffer += strings.ToLower(string(msg.Runes))
+		a.focusMatch(sl, items)
+		return true
+	case tea.KeyBackspace:
+		if a.buffer == "" {
+			return false
+		}
+		a.buffer = trimLastRune(a.buffer)
+		a.lastInput = time.Now()
+		if a.buffer == "" {
+			return true
+		}
+		a.focusMatch(sl, items)
+		return true
+	case tea.KeyEsc:
+		if a.buffer == "" {
+			return false
+		}
+		a.buffer = ""
+		a.lastInput = time.Time{}
+		return true
+	default:
+		return false
+	}
+}
+
+func (a *autocompleteState) focusMatch(sl *selector.Model, items navList) {
+	if idx := a.findMatch(sl, items); idx >= 0 {
+		moveSelectorTo(sl, items, idx)
+	}
+}
+
+// findMatch is the first item from the cursor on that matches the search, -1 for none.
+func (a *autocompleteState) findMatch(sl *selector.Model, items navList) int {
+	if a.buffer == "" || items.len() == 0 {
+		return -1
+	}
+	query := strings.ToLower(a.buffer)
+	total := items.len()
+	start := sl.Index()
+	for offset := 0; offset < total; offset++ {
+		idx := (start + offset) % total
+		if items.skip(idx) {
+			continue
+		}
+		label, hint, value := items.text(idx)
+		if strings.Contains(strings.ToLower(label), query) || (hint != "" && strings.Contains(strings.ToLower(hint), query)) || strings.Contains(strings.ToLower(value), query) {
+			return idx
+		}
+	}
+	return -1
+}
+
+func trimLastRune(value string) string {
+	if value == "" {
+		return value
+	}
+	_, size := utf8.DecodeLastRuneInString(value)
+	if size <= 0 || size > len(value) {
+		return ""
+	}
+	return value[:len(value)-size]
+}
+
+func isAutocompleteRune(r rune) bool {
+	if unicode.IsLetter(r) {
+		return true
+	}
+	if unicode.IsDigit(r) {
+		return true
+	}
+	switch r {
+	case '-', '_', '.', '/', '+', '#':
+		return true
+	default:
+		return false
+	}
+}
+
+func formatAutocompleteFooter(base, buffer string) string {
+	hint := "Type to search"
+	if buffer != "" {
+		hint = fmt.Sprintf("Filter: %s", buffer)
+	}
+	base = strings.TrimSpace(base)
+	if base == "" {
+		return hint
+	}
+	return fmt.Sprintf("%s  |  %s", base, hint)
+}
@@ -0,0 +1,351 @@
+package prompts
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const liveFrameInterval = 80 * time.Millisecond
+
+// Colors of the icon of a finished spinner or progress bar.
+const (
+	successColor = "2"
+	failureColor = "1"
+)
+
+// liveEntry is a line (or block) rendered by the shared live renderer, e.g. a spinner task.
+type liveEntry interface {
+	// view renders the entry for the given animation frame.
+	view(frame int, now time.Time) string
+	// done reports whether the entry reached a final state and no longer animates.
+	done() bool
+	// interrupt moves a still-running entry to a failed state after Ctrl+C.
+	interrupt(now time.Time)
+}
+
+// liveSession is one run of the live program. It ends once every entry is done
+// (or on Ctrl+C) and its last frame stays on screen.
+type liveSession struct {
+	closed bool
+	// paused ends the program without a final frame because a prompt takes the terminal
+	paused   bool
+	final    string
+	finished chan struct{}
+}
+
+// liveRenderer owns the terminal while spinners and progress bars are running, so that
+// concurrent entries are drawn by a single Bubble Tea program instead of fighting over the cursor.
+type liveRenderer struct {
+	mu      sync.Mutex
+	nextID  int
+	order   []int
+	entries map[int]liveEntry
+	session *liveSession
+	// previous is the last session that was started, the next one waits for it to restore the terminal
+	previous *liveSession
+	// paused is set while a prompt owns the terminal: entries keep their state but are not drawn
+	paused bool
+}
+
+var live = &liveRenderer{entries: make(map[int]liveEntry)}
+
+// liveIsInteractive reports whether entries are animated; otherwise only their final line is printed.
+func liveIsInteractive() bool {
+	return !isCIEnvironment() && isFullyInteractiveTTY()
+}
+
+// add registers a new entry and starts the live program if needed. It returns the entry handle.
+func (r *liveRenderer) add(entry liveEntry) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	id := r.nextID
+	r.entries[id] = entry
+	r.order = append(r.order, id)
+
+	if r.session == nil && !r.paused && liveIsInteractive() {
+		r.startLocked()
+	}
+	return id
+}
+
+// startLocked starts a live program for the current entries.
+func (r *liveRenderer) startLocked() {
+	session := &liveSession{finished: make(chan struct{})}
+	go r.run(session, r.previous)
+	r.session = session
+	r.previous = session
+}
+
+// pause gives the terminal to a prompt: the live program quits and pause waits until it
+// has restored the terminal. Entries keep running and are drawn again on resume.
+func (r *liveRenderer) pause() {
+	r.mu.Lock()
+	r.paused = true
+	if r.session != nil {
+		r.session.paused = true
+		r.session = nil
+	}
+	previous := r.previous
+	r.mu.Unlock()
+	if previous != nil {
+		<-previous.finished
+	}
+}
+
+// resume takes the terminal back once no prompt owns it. Running entries are animated
+// again, entries that finished in the meantime print their final line.
+func (r *liveRenderer) resume() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.paused {
+		return
+	}
+	r.paused = false
+	if r.session != nil {
+		return
+	}
+	for _, entry := range r.entries {
+		if !entry.done() && liveIsInteractive() {
+			r.startLocked()
+			return
+		}
+	}
+	now := time.Now()
+	_, output := terminalIO()
+	for _, id := range append([]int(nil), r.order...) {
+		if entry := r.entries[id]; entry.done() {
+			fmt.Fprintln(output, entry.view(0, now))
+			r.removeLocked(id)
+		}
+	}
+}
+
+// update calls fn with the entry of the given handle while holding the renderer lock.
+// It reports whether the handle exists.
+func (r *liveRenderer) update(id int, fn func(entry liveEntry)) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[id]
+	if !ok {
+		return false
+	}
+	fn(entry)
+	return true
+}
+
+// finish calls fn to move the entry of the given handle to its final state. Once no
+// entry is running anymore the live program is stopped and finish waits until the
+// terminal is restored, so that output written afterwards does not interleave.
+func (r *liveRenderer) finish(id int, fn func(entry liveEntry)) bool {
+	r.mu.Lock()
+
+	entry, ok := r.entries[id]
+	if !ok {
+		r.mu.Unlock()
+		return false
+	}
+	fn(entry)
+
+	if r.session == nil && r.paused {
+		// A prompt owns the terminal, the final line is printed on resume
+		r.mu.Unlock()
+		return true
+	}
+	if r.session == nil {
+		// Not animated, print the final line right away
+		_, output := terminalIO()
+		fmt.Fprintln(output, entry.view(0, time.Now()))
+		r.removeLocked(id)
+		r.mu.Unlock()
+		return true
+	}
+
+	session := r.closeIfDoneLocked()
+	r.mu.Unlock()
+	if session != nil {
+		<-session.finished
+	}
+	return true
+}
+
+// remove drops the entry of the given handle without leaving a final line.
+func (r *liveRenderer) remove(id int) bool {
+	r.mu.Lock()
+
+	if _, ok := r.entries[id]; !ok {
+		r.mu.Unlock()
+		return false
+	}
+	r.removeLocked(id)
+
+	var session *liveSession
+	if r.session != nil {
+		session = r.closeIfDoneLocked()
+	}
+	r.mu.Unlock()
+	if session != nil {
+		<-session.finished
+	}
+	return true
+}
+
+func (r *liveRenderer) removeLocked(id int) {
+	delete(r.entries, id)
+	for i, entryID := range r.order {
+		if entryID == id {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// closeIfDoneLocked ends the current session when every entry is done and returns it.
+func (r *liveRenderer) closeIfDoneLocked() *liveSession {
+	for _, entry := range r.entries {
+		if !entry.done() {
+			return nil
+		}
+	}
+	return r.closeLocked(time.Now())
+}
+
+// closeLocked freezes the final frame of the current session and forgets its entries.
+func (r *liveRenderer) closeLocked(now time.Time) *liveSession {
+	session := r.session
+	session.final = r.viewLocked(0, now)
+	session.closed = true
+	r.session = nil
+	r.entries = make(map[int]liveEntry)
+	r.order = nil
+	return session
+}
+
+func (r *liveRenderer) viewLocked(frame int, now time.Time) string {
+	lines := make([]string, 0, len(r.order))
+	for _, id := range r.order {
+		lines = append(lines, r.entries[id].view(frame, now))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// run drives the live program of a session on its own goroutine.
+func (r *liveRenderer) run(session, previous *liveSession) {
+	defer close(session.finished)
+
+	if previous != nil {
+		<-previous.finished
+	}
+
+	m := &liveModel{renderer: r, session: session}
+	opts, done := terminalProgramOptions()
+	defer done()
+	p := tea.NewProgram(m, opts...)
+	if err := p.Start(); err != nil {
+		r.mu.Lock()
+		if r.session == session {
+			r.closeLocked(time.Now())
+		}
+		r.mu.Unlock()
+		return
+	}
+
+	if m.interrupted {
+		// Hand the interrupt back to the host process now that the terminal is restored
+		if process, err := os.FindProcess(os.Getpid()); err == nil {
+			_ = process.Signal(os.Interrupt)
+		}
+	}
+}
+
+type liveFrameMsg struct{}
+
+type liveModel struct {
+	renderer    *liveRenderer
+	session     *liveSession
+	frame       int
+	final       string
+	quitting    bool
+	interrupted bool
+}
+
+func (m *liveModel) Init() tea.Cmd {
+	return m.nextFrame()
+}
+
+func (m *liveModel) nextFrame() tea.Cmd {
+	return tea.Tick(liveFrameInterval, func(time.Time) tea.Msg {
+		return liveFrameMsg{}
+	})
+}
+
+func (m *liveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() != "ctrl+c" {
+			return m, nil
+		}
+		// Fail everything that is still running and give the terminal back
+		r := m.renderer
+		r.mu.Lock()
+		if m.session.paused {
+			// A prompt is taking the terminal and handles Ctrl+C itself
+			r.mu.Unlock()
+			m.quitting = true
+			return m, tea.Quit
+		}
+		if !m.session.closed {
+			now := time.Now()
+			for _, entry := range r.entries {
+				if !entry.done() {
+					entry.interrupt(now)
+				}
+			}
+			r.closeLocked(now)
+		}
+		m.final = m.session.final
+		r.mu.Unlock()
+		m.quitting = true
+		m.interrupted = true
+		return m, tea.Quit
+	case liveFrameMsg:
+		m.frame++
+		r := m.renderer
+		r.mu.Lock()
+		closed, paused := m.session.closed, m.session.paused
+		m.final = m.session.final
+		r.mu.Unlock()
+		if closed || paused {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		return m, m.nextFrame()
+	}
+	return m, nil
+}
+
+func (m *liveModel) View() string {
+	if m.quitting {
+		if m.final == "" {
+			return ""
+		}
+		return m.final + "\n"
+	}
+	r := m.renderer
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if m.session.closed {
+		return m.session.final + "\n"
+	}
+	if m.session.paused {
+		return ""
+	}
+	return r.viewLocked(m.frame, time.Now())
+}
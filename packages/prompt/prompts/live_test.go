@@ -0,0 +1,46 @@
+package prompts
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLiveFinalLinesWaitForPromptToRelease(t *testing.T) {
+	var out bytes.Buffer
+	SetTerminal(strings.NewReader(""), &out)
+	defer SetTerminal(nil, nil)
+
+	s := newPromptSession()
+	if acquired, err := s.acquire(); !acquired || err != nil {
+		t.Fatalf("acquire = %v, %v", acquired, err)
+	}
+	live.pause()
+
+	id := SpinnerStart("building")
+	if !SpinnerSucceed(id, "built") {
+		t.Fatal("unknown spinner handle")
+	}
+	if out.Len() != 0 {
+		t.Fatalf("spinner wrote %q while a prompt owned the terminal", out.String())
+	}
+
+	s.release()
+	if !strings.Contains(out.String(), "built") {
+		t.Fatalf("final line %q not printed after release", out.String())
+	}
+	if SpinnerStop(id) {
+		t.Fatal("finished spinner is still registered")
+	}
+}
+
+func TestSpinnerStyledView(t *testing.T) {
+	started := time.Now()
+	task := &spinnerTask{text: "building", started: started, frames: []string{"a", "b", "c"}, delay: time.Second, hideElapsed: true}
+
+	// The delay picks the frame, not the frame counter of the renderer
+	if got := ansiEscape.ReplaceAllString(task.view(0, started.Add(2500*time.Millisecond)), ""); got != "c building" {
+		t.Fatalf("view = %q, want %q", got, "c building")
+	}
+}
@@ -0,0 +1,137 @@
+package prompts
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Concurrency policies for prompts that are opened while another prompt owns the terminal.
+const (
+	// ConcurrencyQueue waits until the open prompt is done (default)
+	ConcurrencyQueue = "queue"
+	// ConcurrencyReject fails the new prompt with a "busy" error
+	ConcurrencyReject = "reject"
+	// ConcurrencyPreempt cancels the open prompt and shows the new one
+	ConcurrencyPreempt = "preempt"
+)
+
+// preemptedReason is the cancel reason reported by a prompt that was preempted.
+const preemptedReason = "preempted by another prompt"
+
+// promptLock makes sure only one program reads the terminal at a time.
+var promptLock = struct {
+	sync.Mutex
+	owner *promptSession
+	// next is the prompt that preempted the owner, it takes over the lock on release
+	next     *promptSession
+	released chan struct{}
+	policy   string
+}{policy: concurrencyPolicyFromEnv()}
+
+func concurrencyPolicyFromEnv() string {
+	policy := strings.ToLower(strings.TrimSpace(os.Getenv("DLER_PROMPT_CONCURRENCY")))
+	if !isConcurrencyPolicy(policy) {
+		return ConcurrencyQueue
+	}
+	return policy
+}
+
+func isConcurrencyPolicy(policy string) bool {
+	switch policy {
+	case ConcurrencyQueue, ConcurrencyReject, ConcurrencyPreempt:
+		return true
+	}
+	return false
+}
+
+// SetConcurrencyPolicy changes how concurrent prompts are handled: "queue", "reject" or
+// "preempt". It returns false for unknown policies. The initial policy is read from
+// DLER_PROMPT_CONCURRENCY.
+func SetConcurrencyPolicy(policy string) bool {
+	policy = strings.ToLower(strings.TrimSpace(policy))
+	if !isConcurrencyPolicy(policy) {
+		return false
+	}
+	promptLock.Lock()
+	promptLock.policy = policy
+	promptLock.Unlock()
+	return true
+}
+
+// acquire takes the prompt lock for s according to the current policy. It returns
+// false without an error when s was cancelled before or while waiting. Under the preempt
+// policy only the latest prompt waits; the lock is handed to it on release.
+func (s *promptSession) acquire() (bool, error) {
+	started := time.Now()
+	defer func() {
+		s.mu.Lock()
+		s.waited = time.Since(started)
+		s.mu.Unlock()
+	}()
+
+	for {
+		promptLock.Lock()
+		if s.cancelled() {
+			// A waiter displaced by a later preempting prompt must not take the lock or
+			// preempt the prompt it was handed to
+			promptLock.Unlock()
+			s.release()
+			return false, nil
+		}
+		if promptLock.owner == nil {
+			promptLock.owner = s
+			promptLock.released = make(chan struct{})
+		}
+		if promptLock.owner == s {
+			promptLock.Unlock()
+			return true, nil
+		}
+		owner, released := promptLock.owner, promptLock.released
+		switch promptLock.policy {
+		case ConcurrencyReject:
+			promptLock.Unlock()
+			return false, ErrBusy
+		case ConcurrencyPreempt:
+			// The latest prompt wins and is handed the lock as soon as the owner quits; a
+			// prompt that was waiting for it is cancelled like the owner
+			if next := promptLock.next; next != nil && next != s {
+				next.cancel(preemptedReason)
+			}
+			promptLock.next = s
+			owner.cancel(preemptedReason)
+		}
+		promptLock.Unlock()
+
+		select {
+		case <-released:
+		case <-s.cancelCh:
+			// The lock may have been handed over in the meantime
+			s.release()
+			return false, nil
+		}
+	}
+}
+
+// release gives the prompt lock back (or to the prompt that preempted s) and wakes up waiting
+// prompts. Spinners and progress bars paused by the prompt resume once the lock is free.
+func (s *promptSession) release() {
+	promptLock.Lock()
+	defer promptLock.Unlock()
+	if promptLock.next == s {
+		promptLock.next = nil
+	}
+	if promptLock.owner != s {
+		return
+	}
+	close(promptLock.released)
+	promptLock.owner = promptLock.next
+	promptLock.next = nil
+	if promptLock.owner != nil {
+		promptLock.released = make(chan struct{})
+		return
+	}
+	// No prompt takes over, the live renderer may draw again
+	live.resume()
+}
@@ -0,0 +1,114 @@
+package prompts
+
+import (
+	"testing"
+	"time"
+)
+
+type acquireResult struct {
+	acquired bool
+	err      error
+}
+
+func startAcquire(s *promptSession) chan acquireResult {
+	done := make(chan acquireResult, 1)
+	go func() {
+		acquired, err := s.acquire()
+		done <- acquireResult{acquired, err}
+	}()
+	return done
+}
+
+// waitForNext blocks until s is the prompt the lock will be handed to.
+func waitForNext(t *testing.T, s *promptSession) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		promptLock.Lock()
+		next := promptLock.next
+		promptLock.Unlock()
+		if next == s {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("session never became the next owner")
+}
+
+func receiveAcquire(t *testing.T, done chan acquireResult) acquireResult {
+	t.Helper()
+	select {
+	case r := <-done:
+		return r
+	case <-time.After(2 * time.Second):
+		t.Fatal("acquire did not return")
+		return acquireResult{}
+	}
+}
+
+func TestPreemptLatestPromptWins(t *testing.T) {
+	promptLock.Lock()
+	policy := promptLock.policy
+	promptLock.Unlock()
+	SetConcurrencyPolicy(ConcurrencyPreempt)
+	defer SetConcurrencyPolicy(policy)
+
+	a, b, c := newPromptSession(), newPromptSession(), newPromptSession()
+	if acquired, err := a.acquire(); !acquired || err != nil {
+		t.Fatalf("first acquire = %v, %v", acquired, err)
+	}
+	doneB := startAcquire(b)
+	waitForNext(t, b)
+	doneC := startAcquire(c)
+	waitForNext(t, c)
+
+	if r := receiveAcquire(t, doneB); r.acquired || r.err != nil {
+		t.Fatalf("displaced prompt acquire = %v, %v", r.acquired, r.err)
+	}
+	for name, s := range map[string]*promptSession{"owner": a, "displaced prompt": b} {
+		if !s.cancelled() || s.meta(nil).Reason != preemptedReason {
+			t.Fatalf("%s was not preempted: %+v", name, s.meta(nil))
+		}
+	}
+
+	// The owner quits once it sees the cancellation
+	a.release()
+	if r := receiveAcquire(t, doneC); !r.acquired || r.err != nil {
+		t.Fatalf("latest prompt acquire = %v, %v", r.acquired, r.err)
+	}
+	if c.cancelled() {
+		t.Fatalf("latest prompt was cancelled: %+v", c.meta(nil))
+	}
+	c.release()
+
+	promptLock.Lock()
+	owner, next := promptLock.owner, promptLock.next
+	promptLock.Unlock()
+	if owner != nil || next != nil {
+		t.Fatalf("lock not released: owner %p, next %p", owner, next)
+	}
+}
+
+func TestQueueWaitsForRelease(t *testing.T) {
+	promptLock.Lock()
+	policy := promptLock.policy
+	promptLock.Unlock()
+	SetConcurrencyPolicy(ConcurrencyQueue)
+	defer SetConcurrencyPolicy(policy)
+
+	a, b := newPromptSession(), newPromptSession()
+	if acquired, err := a.acquire(); !acquired || err != nil {
+		t.Fatalf("first acquire = %v, %v", acquired, err)
+	}
+	doneB := startAcquire(b)
+	select {
+	case r := <-doneB:
+		t.Fatalf("acquire returned %v, %v while the lock was owned", r.acquired, r.err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	a.release()
+	if r := receiveAcquire(t, doneB); !r.acquired || r.err != nil {
+		t.Fatalf("queued acquire = %v, %v", r.acquired, r.err)
+	}
+	b.release()
+}
@@ -3,41 +3,48 @@ package prompts
 import (
 	"encoding/json"
 	"fmt"
-	"os"
-	"strings"
+	"sort"
+	"strconv"
 	"time"
-	"unicode"
 
 	"github.com/mritd/bubbles/common"
 
 	"github.com/mritd/bubbles/selector"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"golang.org/x/term"
 )
 
 type multiselectModel struct {
-	sl                    selector.Model
-	selected              map[int]bool
-	items                 []ListItem
-	headerText            string
-	footerText            string
-	canceled              bool
-	ctrlCPressedOnce      bool
-	ctrlCPressTime        time.Time
-	showCancelMsg         bool
-	autocompleteEnabled   bool
-	autocompleteBuffer    string
-	autocompleteLastInput time.Time
+	sl               selector.Model
+	selected         map[int]bool
+	items            []ListItem
+	headerText       string
+	footerText       string
+	canceled         bool
+	ctrlCPressedOnce bool
+	ctrlCPressTime   time.Time
+	showCancelMsg    bool
+	autocomplete     autocompleteState
+	backKey          string
+	wentBack         bool
+	timeout          promptTimeout
+	// preselected are the indices checked initially, returned on timeout
+	preselected []int
+	other       *otherEntry
+	rng         *rangeSelection
 }
 
 func (m multiselectModel) Init() tea.Cmd {
-	return nil
+	return m.timeout.init()
 }
 
 type multiselectResetCancelMsg struct{}
 
 func (m *multiselectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if handled, cmd := m.timeout.update(msg); handled {
+		return m, cmd
+	}
+
 	// Handle double Ctrl+C first - must intercept before selector sees it
 	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "ctrl+c" {
 		now := time.Now()
@@ -65,19 +72,47 @@ func (m *multiselectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// While the "Other…" text field is shown it receives every key
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.other.typing {
+		submitted, cmd := m.other.update(keyMsg)
+		if submitted {
+			m.selected[m.other.index] = true
+		}
+		return m, cmd
+	}
+
+	// Handle back navigation before the selector or autocomplete see the key
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && isBackKey(keyMsg, m.backKey) {
+		m.wentBack = true
+		return m, tea.Quit
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if m.handleAutocompleteKey(msg) {
+		if m.autocomplete.handleKey(msg, &m.sl, listItems(m.items)) {
 			return m, nil
 		}
 		switch msg.String() {
 		case " ":
 			// Toggle selection on space
 			currentIndex := m.sl.Index()
-			// Prevent toggling disabled items
-			if currentIndex < len(m.items) && m.items[currentIndex].Disabled {
+			if m.rng.spans(currentIndex) {
+				m.rng.toggle(m.selected, currentIndex, m.rangeable)
 				return m, nil
 			}
+			m.rng.clear()
+			// Prevent toggling disabled items and sections
+			if currentIndex < len(m.items) && m.items[currentIndex].skipped() {
+				return m, nil
+			}
+			// Checking "Other…" asks for its text first, unchecking it drops the text
+			if m.other.is(currentIndex) && !m.selected[currentIndex] {
+				m.other.open()
+				return m, nil
+			}
+			if m.other.is(currentIndex) {
+				m.other.value = ""
+			}
 			if m.selected[currentIndex] {
 				delete(m.selected, currentIndex)
 			} else {
@@ -90,20 +125,40 @@ func (m *multiselectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case "up", "k":
 			// Move up, skipping disabled items
+			m.rng.moved()
+			prev := m.sl.Index()
 			_, cmd := m.sl.Update(msg)
-			// After update, check if we're on a disabled item and skip if needed
-			for m.sl.Index() < len(m.items) && m.items[m.sl.Index()].Disabled {
-				_, cmd = m.sl.Update(tea.KeyMsg{Type: tea.KeyUp})
-			}
+			skipDisabled(&m.sl, listItems(m.items), prev, -1)
 			return m, cmd
 		case "down", "j":
 			// Move down, skipping disabled items
+			m.rng.moved()
+			prev := m.sl.Index()
 			_, cmd := m.sl.Update(msg)
-			// After update, check if we're on a disabled item and skip if needed
-			for m.sl.Index() < len(m.items) && m.items[m.sl.Index()].Disabled {
-				_, cmd = m.sl.Update(tea.KeyMsg{Type: tea.KeyDown})
-			}
+			skipDisabled(&m.sl, listItems(m.items), prev, 1)
 			return m, cmd
+		case "shift+up":
+			// Extend the range from the anchor
+			prev := m.sl.Index()
+			m.rng.extend(prev)
+			_, cmd := m.sl.Update(tea.KeyMsg{Type: tea.KeyUp})
+			skipDisabled(&m.sl, listItems(m.items), prev, -1)
+			return m, cmd
+		case "shift+down":
+			prev := m.sl.Index()
+			m.rng.extend(prev)
+			_, cmd := m.sl.Update(tea.KeyMsg{Type: tea.KeyDown})
+			skipDisabled(&m.sl, listItems(m.items), prev, 1)
+			return m, cmd
+		case "ctrl+v", "v":
+			// v only reaches here without autocomplete, which takes letters for the search
+			m.rng.toggleVisual(m.sl.Index())
+			return m, nil
+		case "esc":
+			if m.rng.active() {
+				m.rng.clear()
+				return m, nil
+			}
 		}
 	}
 
@@ -113,6 +168,8 @@ func (m *multiselectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m multiselectModel) View() string {
 	view := m.sl.View()
+	view += m.other.view()
+	view += m.timeout.view()
 	if m.showCancelMsg {
 		view += "\n" + common.FontColor("Press Ctrl+C again to exit", "yellow")
 	}
@@ -121,280 +178,146 @@ func (m multiselectModel) View() string {
 
 type MultiselectResult struct {
 	SelectedIndices []string `json:"selectedIndices"`
-	Error           string   `json:"error"`
-}
-
-type multiselectWaitForResizeModel struct {
-	minHeight int
-	message   string
-}
-
-func (m multiselectWaitForResizeModel) Init() tea.Cmd {
-	return tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
-		return multiselectCheckTerminalSizeMsg{}
-	})
-}
-
-type multiselectCheckTerminalSizeMsg struct{}
-
-func (m multiselectWaitForResizeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		if msg.Height >= m.minHeight {
-			return m, tea.Quit
-		}
-		return m, tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
-			return multiselectCheckTerminalSizeMsg{}
-		})
-	case multiselectCheckTerminalSizeMsg:
-		fd := int(os.Stdout.Fd())
-		_, height, err := term.GetSize(fd)
-		if err == nil && height >= m.minHeight {
-			return m, tea.Quit
-		}
-		return m, tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
-			return multiselectCheckTerminalSizeMsg{}
-		})
-	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" {
-			return m, tea.Quit
-		}
-	}
-	return m, nil
+	// CustomValue is the text typed for the "Other…" entry; Custom marks such an answer
+	CustomValue string `json:"customValue,omitempty"`
+	Custom      bool   `json:"custom,omitempty"`
+	resultMeta
 }
 
-func (m multiselectWaitForResizeModel) View() string {
-	fd := int(os.Stdout.Fd())
-	_, height, err := term.GetSize(fd)
-	if err != nil {
-		return fmt.Sprintf("Error checking terminal size: %s\n", err)
-	}
-	return fmt.Sprintf("\n%s\n\nCurrent height: %d | Required: %d\n\nPlease resize your terminal window to continue...\n", m.message, height, m.minHeight)
+// rangeable reports whether a range checks the item at idx; "Other…" needs its text and
+// is left out.
+func (m *multiselectModel) rangeable(idx int) bool {
+	return idx < len(m.items) && !m.items[idx].skipped()
 }
 
-func multiselectWaitForTerminalResize(minHeight int, message string) error {
-	m := multiselectWaitForResizeModel{
-		minHeight: minHeight,
-		message:   message,
-	}
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	err := p.Start()
-	if err != nil {
-		return err
-	}
-
-	// Block until terminal is large enough
-	fd := int(os.Stdout.Fd())
-	for {
-		_, height, err := term.GetSize(fd)
-		if err == nil && height >= minHeight {
-			break
-		}
-		time.Sleep(time.Second / 2)
-	}
-	return nil
+func Multiselect(jsonData, headerText, footerText string, perPage int, autocomplete bool, preselectedValues, initialCursorValue, backKey string, timeout int, resetTimeoutOnKey bool, otherLabel string) string {
+	return multiselect(newPromptSession(), jsonData, headerText, footerText, perPage, autocomplete, preselectedValues, initialCursorValue, backKey, timeout, resetTimeoutOnKey, otherLabel)
 }
 
-func (m *multiselectModel) handleAutocompleteKey(msg tea.KeyMsg) bool {
-	if !m.autocompleteEnabled || len(m.items) == 0 {
-		return false
+func multiselect(s *promptSession, jsonData, headerText, footerText string, perPage int, autocomplete bool, preselectedValues, initialCursorValue, backKey string, timeout int, resetTimeoutOnKey bool, otherLabel string) string {
+	var items []ListItem
+	if err := parseJSONArg(jsonData, "items", &items); err != nil {
+		result, _ := json.Marshal(&MultiselectResult{SelectedIndices: []string{}, resultMeta: s.meta(err)})
+		return string(result)
 	}
-	switch msg.Type {
-	case tea.KeyRunes:
-		if len(msg.Runes) == 0 {
-			return false
-		}
-		if !m.autocompleteLastInput.IsZero() && time.Since(m.autocompleteLastInput) > autocompleteResetTimeout {
-			m.autocompleteBuffer = ""
-		}
-		r := msg.Runes[0]
-		if unicode.IsSpace(r) {
-			return false
-		}
-		if unicode.IsDigit(r) && m.autocompleteBuffer == "" {
-			return false
-		}
-		if !isAutocompleteRune(r) {
-			return false
-		}
-		m.autocompleteLastInput = time.Now()
-		m.autocompleteBuffer += strings.ToLower(string(msg.Runes))
-		m.focusAutocompleteMatch()
-		return true
-	case tea.KeyBackspace:
-		if m.autocompleteBuffer == "" {
-			return false
-		}
-		m.autocompleteBuffer = trimLastRune(m.autocompleteBuffer)
-		m.autocompleteLastInput = time.Now()
-		if m.autocompleteBuffer == "" {
-			return true
-		}
-		m.focusAutocompleteMatch()
-		return true
-	case tea.KeyEsc:
-		if m.autocompleteBuffer == "" {
-			return false
+	// Parse preselectedValues (JSON array of strings) - used for preselection
+	var preselected []string
+	if preselectedValues != "" {
+		if err := parseJSONArg(preselectedValues, "preselected values", &preselected); err != nil {
+			result, _ := json.Marshal(&MultiselectResult{SelectedIndices: []string{}, resultMeta: s.meta(err)})
+			return string(result)
 		}
-		m.autocompleteBuffer = ""
-		m.autocompleteLastInput = time.Time{}
-		return true
-	default:
-		return false
-	}
-}
-
-func (m *multiselectModel) focusAutocompleteMatch() {
-	if m.autocompleteBuffer == "" {
-		return
 	}
-	idx := m.findAutocompleteMatch()
-	if idx >= 0 {
-		m.moveSelectorTo(idx)
+	o := MultiselectOptions{
+		PromptOptions: PromptOptions{
+			Header:            headerText,
+			Footer:            footerText,
+			BackKey:           backKey,
+			Timeout:           time.Duration(timeout) * time.Second,
+			ResetTimeoutOnKey: resetTimeoutOnKey,
+		},
+		PerPage:      perPage,
+		Autocomplete: autocomplete,
+		Preselected:  preselected,
+		Initial:      initialCursorValue,
+		other:        otherLabel,
 	}
+	selected, custom, err := o.run(s, items)
+	result, _ := json.Marshal(&MultiselectResult{
+		SelectedIndices: formatIndices(selected),
+		CustomValue:     custom,
+		Custom:          custom != "",
+		resultMeta:      s.meta(err),
+	})
+	return string(result)
 }
 
-func (m *multiselectModel) findAutocompleteMatch() int {
-	if m.autocompleteBuffer == "" || len(m.items) == 0 {
-		return -1
+// formatIndices converts indices to the strings reported by the string based API.
+func formatIndices(indices []int) []string {
+	formatted := make([]string, 0, len(indices))
+	for _, idx := range indices {
+		formatted = append(formatted, strconv.Itoa(idx))
 	}
-	query := strings.ToLower(m.autocompleteBuffer)
-	total := len(m.items)
-	start := m.sl.Index()
-	for offset := 0; offset < total; offset++ {
-		idx := (start + offset) % total
-		if idx < 0 || idx >= total {
-			continue
-		}
-		item := m.items[idx]
-		if item.Disabled {
-			continue
-		}
-		label := strings.ToLower(item.Label)
-		hint := strings.ToLower(item.Hint)
-		value := strings.ToLower(item.Value)
-		if strings.Contains(label, query) || (item.Hint != "" && strings.Contains(hint, query)) || strings.Contains(value, query) {
-			return idx
-		}
-	}
-	return -1
+	return formatted
 }
 
-func (m *multiselectModel) moveSelectorTo(target int) {
-	if target < 0 || target >= len(m.items) {
-		return
-	}
-	guard := 0
-	for m.sl.Index() != target && guard < len(m.items)*2 {
-		if m.sl.Index() < target {
-			if !m.stepSelector(1) {
-				break
-			}
-		} else {
-			if !m.stepSelector(-1) {
-				break
-			}
+// sortedIndices returns the indices of selected accepted by keep in ascending order.
+func sortedIndices(selected map[int]bool, keep func(idx int) bool) []int {
+	indices := []int{}
+	for idx, ok := range selected {
+		if ok && keep(idx) {
+			indices = append(indices, idx)
 		}
-		guard++
 	}
+	sort.Ints(indices)
+	return indices
 }
 
-func (m *multiselectModel) stepSelector(direction int) bool {
-	key := tea.KeyMsg{Type: tea.KeyDown}
-	if direction < 0 {
-		key = tea.KeyMsg{Type: tea.KeyUp}
+func (o MultiselectOptions) run(s *promptSession, item []ListItem) ([]int, string, error) {
+	if err := o.validate(item); err != nil {
+		return nil, "", err
 	}
-	prev := m.sl.Index()
-	m.sl.Update(key)
-	if m.sl.Index() == prev {
-		return false
+	perPage := o.PerPage
+	if perPage <= 0 {
+		perPage = 5
 	}
-	for m.sl.Index() < len(m.items) && m.items[m.sl.Index()].Disabled {
-		prev = m.sl.Index()
-		m.sl.Update(key)
-		if m.sl.Index() == prev {
-			return false
-		}
-	}
-	return true
-}
 
-func Multiselect(jsonData, headerText, footerText string, perPage int, autocomplete bool, preselectedValues, initialCursorValue string) string {
 	// Minimum height: header (1) + perPage items + footer (1) + buffer (2) = perPage + 4
 	minTerminalHeight := perPage + 4
 	if minTerminalHeight < 5 {
 		minTerminalHeight = 5
 	}
 
-	var err error
-
-	if shouldValidateTerminalSize() {
-		height, sizeErr := getTerminalHeight()
-		if sizeErr != nil {
-			result, _ := json.Marshal(&MultiselectResult{
-				SelectedIndices: []string{},
-				Error:           fmt.Sprintf("failed to get terminal size: %s", sizeErr),
-			})
-			return string(result)
-		}
-
-		if height < minTerminalHeight {
-			// Wait for user to resize terminal instead of returning error
-			waitMessage := fmt.Sprintf("⚠️  Terminal height too small!\n   Current: %d lines | Required: %d lines (for perPage=%d)", height, minTerminalHeight, perPage)
-			err = multiselectWaitForTerminalResize(minTerminalHeight, waitMessage)
-			if err != nil {
-				result, _ := json.Marshal(&MultiselectResult{
-					SelectedIndices: []string{},
-					Error:           fmt.Sprintf("failed to wait for terminal resize: %s", err),
-				})
-				return string(result)
-			}
-		}
+	m := o.newModel(item, perPage)
+	err := s.run(m, minTerminalHeight)
+	if err != nil {
+		return nil, "", err
+	}
+	if s.cancelled() {
+		return nil, "", ErrCancelled
 	}
+	return m.outcome()
+}
+
+// newModel builds the model for item, showing perPage items at a time.
+func (o MultiselectOptions) newModel(item []ListItem, perPage int) *multiselectModel {
+	headerText, footerText := o.Header, o.Footer
+	initialCursorValue := o.Initial
 
-	var item []ListItem
-	json.Unmarshal([]byte(jsonData), &item)
 	data := []interface{}{}
 	for _, val := range item {
-		data = append(data, ListItem{Value: val.Value, Label: val.Label, Hint: val.Hint, Disabled: val.Disabled})
+		data = append(data, ListItem{Value: val.Value, Label: val.Label, Hint: val.Hint, Disabled: val.Disabled, Kind: val.Kind})
+	}
+	other := newOtherEntry(o.other, len(item))
+	if other.enabled() {
+		data = append(data, ListItem{Label: other.label})
 	}
 
-	// Parse preselectedValues (JSON array of strings) - used for preselection
 	preselectedSet := make(map[string]bool)
-	if preselectedValues != "" {
-		var preselectedVals []string
-		json.Unmarshal([]byte(preselectedValues), &preselectedVals)
-		for _, val := range preselectedVals {
-			preselectedSet[val] = true
-		}
+	for _, val := range o.Preselected {
+		preselectedSet[val] = true
 	}
 
-	// Determine start index based on initialCursorValue (first preselected value or empty)
-	startIndex := 0
+	// Determine start index based on initialCursorValue (first preselected value or empty),
+	// otherwise start on the first item that can be selected
+	startIndex := firstSelectable(item)
 	if initialCursorValue != "" {
 		for i, it := range item {
-			if it.Value == initialCursorValue && !it.Disabled {
+			if it.Value == initialCursorValue && !it.skipped() {
 				startIndex = i
 				break
 			}
 		}
-	} else {
-		// Find first non-disabled item to start on
-		for startIndex < len(item) && item[startIndex].Disabled {
-			startIndex++
-		}
-		if startIndex >= len(item) {
-			startIndex = 0
-		}
 	}
 
 	selected := make(map[int]bool)
 	// Set initial selections based on preselectedValues (acts as preselection)
 	for i, it := range item {
-		if !it.Disabled && preselectedSet[it.Value] {
+		if !it.skipped() && preselectedSet[it.Value] {
 			selected[i] = true
 		}
 	}
+	rng := newRangeSelection()
 	sl := selector.Model{
 		Data:    data,
 		PerPage: perPage,
@@ -411,6 +334,13 @@ func Multiselect(jsonData, headerText, footerText string, perPage int, autocompl
 		},
 		SelectedFunc: func(sl selector.Model, obj interface{}, gdIndex int) string {
 			t := obj.(ListItem)
+			if t.isSection() {
+				return sectionLine(t)
+			}
+			n := optionNumber(item, gdIndex)
+			if other.is(gdIndex) {
+				t.Label = other.itemLabel()
+			}
 			disabled := t.Disabled
 			if gdIndex < len(item) {
 				disabled = item[gdIndex].Disabled
@@ -421,17 +351,24 @@ func Multiselect(jsonData, headerText, footerText string, perPage int, autocompl
 			}
 			if disabled {
 				if t.Hint != "" {
-					return common.FontColor(fmt.Sprintf("%s [%d] %s (%s) (disabled)", prefix, gdIndex+1, t.Label, t.Hint), "240")
+					return common.FontColor(fmt.Sprintf("%s [%d] %s (%s) (disabled)", prefix, n, t.Label, t.Hint), "240")
 				}
-				return common.FontColor(fmt.Sprintf("%s [%d] %s (disabled)", prefix, gdIndex+1, t.Label), "240")
+				return common.FontColor(fmt.Sprintf("%s [%d] %s (disabled)", prefix, n, t.Label), "240")
 			}
 			if t.Hint != "" {
-				return common.FontColor(fmt.Sprintf("%s [%d] %s (%s)", prefix, gdIndex+1, t.Label, t.Hint), selector.ColorSelected)
+				return common.FontColor(fmt.Sprintf("%s [%d] %s (%s)", prefix, n, t.Label, t.Hint), selector.ColorSelected)
 			}
-			return common.FontColor(fmt.Sprintf("%s [%d] %s", prefix, gdIndex+1, t.Label), selector.ColorSelected)
+			return common.FontColor(fmt.Sprintf("%s [%d] %s", prefix, n, t.Label), selector.ColorSelected)
 		},
 		UnSelectedFunc: func(sl selector.Model, obj interface{}, gdIndex int) string {
 			t := obj.(ListItem)
+			if t.isSection() {
+				return sectionLine(t)
+			}
+			n := optionNumber(item, gdIndex)
+			if other.is(gdIndex) {
+				t.Label = other.itemLabel()
+			}
 			disabled := t.Disabled
 			if gdIndex < len(item) {
 				disabled = item[gdIndex].Disabled
@@ -441,9 +378,12 @@ func Multiselect(jsonData, headerText, footerText string, perPage int, autocompl
 				prefix = "✓"
 			}
 			if disabled {
-				return common.FontColor(fmt.Sprintf("%s  %d. %s (disabled)", prefix, gdIndex+1, t.Label), "240")
+				return common.FontColor(fmt.Sprintf("%s  %d. %s (disabled)", prefix, n, t.Label), "240")
+			}
+			if gdIndex < len(item) && rng.contains(gdIndex, sl.Index()) {
+				return common.FontColor(fmt.Sprintf("%s ┃%d. %s", prefix, n, t.Label), selector.ColorSelected)
 			}
-			return common.FontColor(fmt.Sprintf("%s  %d. %s", prefix, gdIndex+1, t.Label), selector.ColorUnSelected)
+			return common.FontColor(fmt.Sprintf("%s  %d. %s", prefix, n, t.Label), selector.ColorUnSelected)
 		},
 		FooterFunc: func(sl selector.Model, obj interface{}, gdIndex int) string {
 			footer := footerText
@@ -458,25 +398,32 @@ func Multiselect(jsonData, headerText, footerText string, perPage int, autocompl
 	}
 
 	m := &multiselectModel{
-		ctrlCPressedOnce:    false,
-		showCancelMsg:       false,
-		selected:            selected,
-		items:               item,
-		headerText:          headerText,
-		footerText:          footerText,
-		autocompleteEnabled: autocomplete,
-		autocompleteBuffer:  "",
-		sl:                  sl,
-	}
+		ctrlCPressedOnce: false,
+		showCancelMsg:    false,
+		selected:         selected,
+		items:            item,
+		headerText:       headerText,
+		footerText:       footerText,
+		autocomplete:     autocompleteState{enabled: o.Autocomplete},
+		sl:               sl,
+		backKey:          o.BackKey,
+		timeout:          newPromptTimeout(o.timeoutSeconds(), o.ResetTimeoutOnKey),
+		other:            other,
+		rng:              rng,
+	}
+	// On timeout, resolve with the preselected values
+	m.preselected = sortedIndices(selected, func(int) bool { return true })
+	m.timeout.hasDefault = len(m.preselected) > 0
 
 	m.sl.FooterFunc = func(sl selector.Model, obj interface{}, gdIndex int) string {
 		footer := footerText
 		if footer == "" {
-			footer = "Space: toggle, Enter: confirm"
+			footer = "Space: toggle, Shift+↑/↓: range, Enter: confirm"
 		}
-		if m.autocompleteEnabled {
-			footer = formatAutocompleteFooter(footer, m.autocompleteBuffer)
+		if m.autocomplete.enabled {
+			footer = formatAutocompleteFooter(footer, m.autocomplete.buffer)
 		}
+		footer = m.rng.footer(footer, sl.Index(), m.rangeable)
 		return common.FontColor(footer, selector.ColorFooter)
 	}
 
@@ -486,32 +433,32 @@ func Multiselect(jsonData, headerText, footerText string, perPage int, autocompl
 		m.sl.Update(tea.KeyMsg{Type: tea.KeyDown})
 	}
 
-	p := tea.NewProgram(m)
-	err = p.Start()
-	if err != nil {
-		result, _ := json.Marshal(&MultiselectResult{
-			SelectedIndices: []string{},
-			Error:           fmt.Sprintf("%s", err),
-		})
-		return string(result)
+	return m
+}
+
+// outcome is the answer of the model once its program has quit.
+func (m *multiselectModel) outcome() ([]int, string, error) {
+	if m.timeout.expired {
+		if !m.timeout.hasDefault {
+			return nil, "", ErrTimeout
+		}
+		return m.preselected, "", nil
 	}
 	if m.canceled || m.sl.Canceled() {
-		result, _ := json.Marshal(&MultiselectResult{
-			SelectedIndices: []string{},
-			Error:           "Cancelled",
-		})
-		return string(result)
-	}
-	indices := []string{}
-	for idx := range m.selected {
-		// Filter out disabled items from results
-		if idx < len(m.items) && !m.items[idx].Disabled {
-			indices = append(indices, fmt.Sprintf("%d", idx))
-		}
+		return nil, "", ErrCancelled
 	}
-	result, _ := json.Marshal(&MultiselectResult{
-		SelectedIndices: indices,
-		Error:           "",
+	// Filter out disabled items and sections from results
+	indices := sortedIndices(m.selected, func(idx int) bool {
+		return idx < len(m.items) && !m.items[idx].skipped()
 	})
-	return string(result)
+	// The text typed for "Other…" counts while the entry is checked
+	custom := ""
+	if m.selected[m.other.index] {
+		custom = m.other.value
+	}
+	if m.wentBack {
+		// The current selection is returned as the partial value
+		return indices, custom, ErrBack
+	}
+	return indices, custom, nil
 }
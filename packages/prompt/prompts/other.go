@@ -0,0 +1,100 @@
+package prompts
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/mritd/bubbles/common"
+
+	"github.com/mritd/bubbles/selector"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// otherEntry is the "Other…" entry a selection can append to its items. Choosing it
+// opens an inline text field, and the typed text is returned as a custom value.
+type otherEntry struct {
+	label string
+	// index is the position of the entry in the selector, right after the last item
+	index  int
+	typing bool
+	input  textinput.Model
+	// value is the submitted text, "" until one was entered
+	value string
+	err   string
+}
+
+// newOtherEntry creates the entry shown after count items; an empty label disables it.
+func newOtherEntry(label string, count int) *otherEntry {
+	input := textinput.NewModel()
+	input.Prompt = ""
+	input.SetCursorMode(textinput.CursorStatic)
+	return &otherEntry{label: label, index: count, input: input}
+}
+
+func (e *otherEntry) enabled() bool {
+	return e.label != ""
+}
+
+// is reports whether idx is the position of the entry.
+func (e *otherEntry) is(idx int) bool {
+	return e.enabled() && idx == e.index
+}
+
+// itemLabel is the label of the entry in the list, followed by the submitted text once there is one.
+func (e *otherEntry) itemLabel() string {
+	if e.value == "" {
+		return e.label
+	}
+	return e.label + " " + e.value
+}
+
+// open shows the text field, pre-filled with the previously submitted text.
+func (e *otherEntry) open() {
+	e.typing = true
+	e.err = ""
+	e.input.SetValue(e.value)
+	e.input.CursorEnd()
+	e.input.Focus()
+}
+
+// update handles a key while the text field is shown. It reports whether a value was
+// submitted; Esc closes the field and keeps the previous value.
+func (e *otherEntry) update(msg tea.KeyMsg) (bool, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		value := strings.TrimSpace(e.input.Value())
+		if value == "" {
+			e.err = "Please enter a value"
+			return false, nil
+		}
+		e.value = value
+		e.typing = false
+		e.input.Blur()
+		return true, nil
+	case tea.KeyEsc:
+		e.typing = false
+		e.input.Blur()
+		return false, nil
+	case tea.KeySpace:
+		// Convert space key to rune message, textinput only handles runes
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}}
+	}
+	var cmd tea.Cmd
+	e.input, cmd = e.input.Update(msg)
+	e.err = ""
+	return false, cmd
+}
+
+// view renders the text field below the list while it is shown.
+func (e *otherEntry) view() string {
+	if !e.typing {
+		return ""
+	}
+	view := "\n" + common.FontColor(e.label, selector.ColorHeader) + " " + e.input.View()
+	if e.err != "" {
+		view += "\n" + common.FontColor("✘ "+e.err, "1")
+	}
+	view += "\n" + common.FontColor("Enter: confirm, Esc: back to the list", selector.ColorFooter)
+	return view
+}
@@ -0,0 +1,269 @@
+package prompts
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mritd/bubbles/common"
+
+	"github.com/mritd/bubbles/selector"
+)
+
+const (
+	progressMinBarWidth = 10
+	progressMaxBarWidth = 40
+	// progressDefaultWidth is used when the terminal width cannot be determined
+	progressDefaultWidth = 80
+	// progressSampleInterval is the minimum time between two throughput samples
+	progressSampleInterval = 250 * time.Millisecond
+	// progressRateSmoothing is the weight of the newest sample in the moving throughput average
+	progressRateSmoothing = 0.3
+)
+
+// progressBar is a single bar rendered by the live renderer. A total of 0 means
+// the size is not known yet; the bar then only shows the transferred amount and rate.
+type progressBar struct {
+	message string
+	total   int64
+	current int64
+	state   taskState
+	started time.Time
+	ended   time.Time
+	// rate is the smoothed throughput in bytes per second
+	rate         float64
+	sampleTime   time.Time
+	sampleAmount int64
+}
+
+func (b *progressBar) add(delta int64, now time.Time) {
+	b.current += delta
+	if b.current < 0 {
+		b.current = 0
+	}
+	if b.total > 0 && b.current > b.total {
+		b.current = b.total
+	}
+
+	elapsed := now.Sub(b.sampleTime)
+	if elapsed < progressSampleInterval {
+		return
+	}
+	sample := float64(b.current-b.sampleAmount) / elapsed.Seconds()
+	if b.rate == 0 {
+		b.rate = sample
+	} else {
+		b.rate = progressRateSmoothing*sample + (1-progressRateSmoothing)*b.rate
+	}
+	b.sampleTime = now
+	b.sampleAmount = b.current
+}
+
+// throughput returns the smoothed rate, or the average rate until the first sample was
+// taken. The time since the last sample counts as well, so the rate falls while no data
+// arrives instead of showing the speed of the last increment.
+func (b *progressBar) throughput(now time.Time) float64 {
+	if b.rate > 0 {
+		elapsed := now.Sub(b.sampleTime)
+		if b.done() || elapsed < progressSampleInterval {
+			return b.rate
+		}
+		pending := float64(b.current-b.sampleAmount) / elapsed.Seconds()
+		// Weigh the pending sample as if one sample had been taken per interval
+		weight := 1 - math.Pow(1-progressRateSmoothing, elapsed.Seconds()/progressSampleInterval.Seconds())
+		return weight*pending + (1-weight)*b.rate
+	}
+	end := now
+	if b.done() {
+		end = b.ended
+	}
+	elapsed := end.Sub(b.started).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(b.current) / elapsed
+}
+
+func (b *progressBar) view(frame int, now time.Time) string {
+	if b.done() {
+		icon := common.FontColor("✔", successColor)
+		if b.state == taskFailed {
+			icon = common.FontColor("✘", failureColor)
+		}
+		stats := fmt.Sprintf("%s %s", formatBytes(b.current), formatElapsed(b.ended.Sub(b.started)))
+		return fmt.Sprintf("%s %s %s", icon, b.message, common.FontColor(stats, selector.ColorFooter))
+	}
+
+	rate := b.throughput(now)
+	var stats string
+	if b.total > 0 {
+		percent := float64(b.current) / float64(b.total) * 100
+		stats = fmt.Sprintf("%3.0f%% %s/%s %s/s ETA %s", percent, formatBytes(b.current), formatBytes(b.total), formatBytes(int64(rate)), formatETA(b.total-b.current, rate))
+	} else {
+		stats = fmt.Sprintf("%s %s/s", formatBytes(b.current), formatBytes(int64(rate)))
+	}
+
+	width, err := getTerminalWidth()
+	if err != nil || width <= 0 {
+		width = progressDefaultWidth
+	}
+	return b.line(frame, width, stats)
+}
+
+// line lays out the message, the bar and the stats of a running bar in width columns.
+func (b *progressBar) line(frame, width int, stats string) string {
+	// Leave room for the message, the stats and the separating spaces
+	barWidth := width - len([]rune(b.message)) - len([]rune(stats)) - 4
+	if barWidth > progressMaxBarWidth {
+		barWidth = progressMaxBarWidth
+	}
+	if barWidth < progressMinBarWidth {
+		// Too narrow for a bar: drop it and shorten the message so the line still fits
+		message := truncateRunes(b.message, width-len([]rune(stats))-2)
+		if message == "" {
+			return common.FontColor(truncateRunes(stats, width-1), selector.ColorFooter)
+		}
+		return fmt.Sprintf("%s %s", message, common.FontColor(stats, selector.ColorFooter))
+	}
+
+	var bar string
+	if b.total > 0 {
+		filled := int(float64(barWidth) * float64(b.current) / float64(b.total))
+		bar = common.FontColor(strings.Repeat("█", filled), selector.ColorSelected) +
+			common.FontColor(strings.Repeat("░", barWidth-filled), selector.ColorUnSelected)
+	} else {
+		// Unknown size, bounce a short block across the bar
+		const block = 3
+		span := barWidth - block
+		pos := frame % (2 * span)
+		if pos > span {
+			pos = 2*span - pos
+		}
+		bar = common.FontColor(strings.Repeat("░", pos), selector.ColorUnSelected) +
+			common.FontColor(strings.Repeat("█", block), selector.ColorSelected) +
+			common.FontColor(strings.Repeat("░", span-pos), selector.ColorUnSelected)
+	}
+	return fmt.Sprintf("%s %s %s", b.message, bar, common.FontColor(stats, selector.ColorFooter))
+}
+
+func (b *progressBar) done() bool {
+	return b.state != taskRunning
+}
+
+func (b *progressBar) interrupt(now time.Time) {
+	b.state = taskFailed
+	b.ended = now
+	b.message += " (cancelled)"
+}
+
+// truncateRunes shortens s to at most n runes, ending it with "…" when it was cut.
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	if n <= 0 {
+		return ""
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. "12.3 MB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value := float64(n) / unit
+	for _, suffix := range []string{"KB", "MB", "GB", "TB"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1f PB", value)
+}
+
+// formatETA renders the remaining time for the given amount at the given rate, or "--" when unknown.
+func formatETA(remaining int64, rate float64) string {
+	if rate <= 0 {
+		return "--"
+	}
+	seconds := int(float64(remaining) / rate)
+	if seconds < 60 {
+		return fmt.Sprintf("%ds", seconds)
+	}
+	if seconds < 3600 {
+		return fmt.Sprintf("%dm %ds", seconds/60, seconds%60)
+	}
+	return fmt.Sprintf("%dh %dm", seconds/3600, (seconds%3600)/60)
+}
+
+// ProgressCreate adds a progress bar and returns its handle. total is the expected
+// amount in bytes, 0 if it is not known yet. Bars stack with other bars and spinners.
+func ProgressCreate(message string, total int64) int {
+	now := time.Now()
+	if total < 0 {
+		total = 0
+	}
+	return live.add(&progressBar{
+		message:    message,
+		total:      total,
+		state:      taskRunning,
+		started:    now,
+		sampleTime: now,
+	})
+}
+
+// ProgressSetTotal changes the expected amount of a bar, e.g. once a Content-Length is known.
+func ProgressSetTotal(id int, total int64) bool {
+	return live.update(id, func(entry liveEntry) {
+		if bar, ok := entry.(*progressBar); ok && !bar.done() {
+			if total < 0 {
+				total = 0
+			}
+			bar.total = total
+			bar.add(0, time.Now())
+		}
+	})
+}
+
+// ProgressIncrement adds delta bytes to a bar.
+func ProgressIncrement(id int, delta int64) bool {
+	return live.update(id, func(entry liveEntry) {
+		if bar, ok := entry.(*progressBar); ok && !bar.done() {
+			bar.add(delta, time.Now())
+		}
+	})
+}
+
+// ProgressSetMessage replaces the label of a bar.
+func ProgressSetMessage(id int, message string) bool {
+	return live.update(id, func(entry liveEntry) {
+		if bar, ok := entry.(*progressBar); ok && !bar.done() {
+			bar.message = message
+		}
+	})
+}
+
+// ProgressFinish completes a bar as succeeded or failed, optionally replacing its label.
+func ProgressFinish(id int, success bool, message string) bool {
+	return live.finish(id, func(entry liveEntry) {
+		bar, ok := entry.(*progressBar)
+		if !ok || bar.done() {
+			return
+		}
+		bar.state = taskFailed
+		if success {
+			bar.state = taskSucceeded
+			if bar.total > 0 {
+				bar.current = bar.total
+			}
+		}
+		bar.ended = time.Now()
+		if message != "" {
+			bar.message = message
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package prompts
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestProgressRateFallsWhileStalled(t *testing.T) {
+	start := time.Now()
+	b := &progressBar{message: "download", total: 1 << 20, started: start, sampleTime: start}
+	b.add(100*1024, start.Add(time.Second))
+	rate := b.throughput(start.Add(time.Second))
+	if rate <= 0 {
+		t.Fatalf("rate = %v after an increment", rate)
+	}
+	if stalled := b.throughput(start.Add(5 * time.Second)); stalled >= rate/2 {
+		t.Fatalf("rate %v did not fall after 4s without data (was %v)", stalled, rate)
+	}
+}
+
+func TestProgressLineFitsNarrowTerminal(t *testing.T) {
+	b := &progressBar{message: "downloading a rather long file name", total: 100, current: 50}
+	stats := "50% 50 B/100 B 10 B/s ETA 5s"
+	for _, width := range []int{20, 40, 60} {
+		line := ansiEscape.ReplaceAllString(b.line(0, width, stats), "")
+		if n := len([]rune(line)); n > width {
+			t.Fatalf("line %q is %d columns wide, terminal has %d", line, n, width)
+		}
+	}
+}
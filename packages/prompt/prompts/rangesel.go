@@ -0,0 +1,123 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rangeSelection is the anchor-based range of a multiple selection. Shift+↑/↓ extends it
+// from the anchor to the cursor, and so do the arrow keys in the visual mode toggled
+// with Ctrl+V (or v without autocomplete). Space checks or unchecks every item of the
+// range at once.
+type rangeSelection struct {
+	// anchor is the index the range started on, -1 while there is no range
+	anchor int
+	visual bool
+}
+
+func newRangeSelection() *rangeSelection {
+	return &rangeSelection{anchor: -1}
+}
+
+func (r *rangeSelection) active() bool {
+	return r.anchor >= 0
+}
+
+// spans reports whether the range covers more than the item under the cursor; a range
+// of that item alone toggles it like Space without a range.
+func (r *rangeSelection) spans(cursor int) bool {
+	return r.active() && r.anchor != cursor
+}
+
+// extend starts a range on cursor unless one is active.
+func (r *rangeSelection) extend(cursor int) {
+	if r.anchor < 0 {
+		r.anchor = cursor
+	}
+}
+
+// toggleVisual enters the visual mode with a range starting on cursor, or leaves it.
+func (r *rangeSelection) toggleVisual(cursor int) {
+	if r.visual {
+		r.clear()
+		return
+	}
+	r.visual = true
+	r.anchor = cursor
+}
+
+// moved ends a range started with Shift when the cursor moves without it.
+func (r *rangeSelection) moved() {
+	if !r.visual {
+		r.clear()
+	}
+}
+
+func (r *rangeSelection) clear() {
+	r.anchor = -1
+	r.visual = false
+}
+
+// bounds are the first and last index of the range.
+func (r *rangeSelection) bounds(cursor int) (int, int) {
+	if r.anchor < cursor {
+		return r.anchor, cursor
+	}
+	return cursor, r.anchor
+}
+
+// contains reports whether idx lies in the range.
+func (r *rangeSelection) contains(idx, cursor int) bool {
+	if !r.active() {
+		return false
+	}
+	lo, hi := r.bounds(cursor)
+	return idx >= lo && idx <= hi
+}
+
+// toggle checks every selectable item of the range, or unchecks them all when all are
+// checked already, and ends the range.
+func (r *rangeSelection) toggle(selected map[int]bool, cursor int, selectable func(idx int) bool) {
+	lo, hi := r.bounds(cursor)
+	all := true
+	for idx := lo; idx <= hi; idx++ {
+		if selectable(idx) && !selected[idx] {
+			all = false
+			break
+		}
+	}
+	for idx := lo; idx <= hi; idx++ {
+		if !selectable(idx) {
+			continue
+		}
+		if all {
+			delete(selected, idx)
+		} else {
+			selected[idx] = true
+		}
+	}
+	r.clear()
+}
+
+// footer adds the size of an active range to the footer text.
+func (r *rangeSelection) footer(base string, cursor int, selectable func(idx int) bool) string {
+	if !r.active() {
+		return base
+	}
+	lo, hi := r.bounds(cursor)
+	count := 0
+	for idx := lo; idx <= hi; idx++ {
+		if selectable(idx) {
+			count++
+		}
+	}
+	hint := fmt.Sprintf("Range: %d items, Space: toggle, Esc: clear", count)
+	if r.visual {
+		hint = "-- VISUAL -- " + hint
+	}
+	base = strings.TrimSpace(base)
+	if base == "" {
+		return hint
+	}
+	return fmt.Sprintf("%s  |  %s", base, hint)
+}
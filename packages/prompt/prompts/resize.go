@@ -0,0 +1,78 @@
+package prompts
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type checkTerminalSizeMsg struct{}
+
+// waitForResizeModel asks the user to enlarge the terminal and quits once it is at least minHeight lines.
+type waitForResizeModel struct {
+	minHeight int
+	height    int
+	canceled  bool
+}
+
+func checkTerminalSize() tea.Cmd {
+	return tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
+		return checkTerminalSizeMsg{}
+	})
+}
+
+func (m *waitForResizeModel) Init() tea.Cmd {
+	return checkTerminalSize()
+}
+
+func (m *waitForResizeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		if m.height >= m.minHeight {
+			return m, tea.Quit
+		}
+	case checkTerminalSizeMsg:
+		if height, err := getTerminalHeight(); err == nil {
+			m.height = height
+		}
+		if m.height >= m.minHeight {
+			return m, tea.Quit
+		}
+		return m, checkTerminalSize()
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.canceled = true
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+func (m *waitForResizeModel) View() string {
+	return fmt.Sprintf("\n⚠️  Terminal height too small!\n\nCurrent height: %d | Required: %d\n\nPlease resize your terminal window to continue...\n", m.height, m.minHeight)
+}
+
+// waitForHeight blocks until the terminal is at least minHeight lines, showing a notice
+// meanwhile. It runs inside the session so it holds the prompt lock and can be cancelled.
+func (s *promptSession) waitForHeight(minHeight int) error {
+	if !shouldValidateTerminalSize() {
+		return nil
+	}
+	height, err := getTerminalHeight()
+	if err != nil {
+		return terminalSizeError(err)
+	}
+	if height >= minHeight {
+		return nil
+	}
+	m := &waitForResizeModel{minHeight: minHeight, height: height}
+	if err := s.start(m, tea.WithAltScreen()); err != nil {
+		return terminalResizeError(err)
+	}
+	if m.canceled {
+		return ErrCancelled
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package prompts
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// JSON-RPC 2.0 error codes.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// rpcMethod handles the params of one method and returns the result payload.
+type rpcMethod func(params json.RawMessage) (json.RawMessage, error)
+
+// rpcMethods mirror the exported prompt functions; the results use the same schemas.
+var rpcMethods = map[string]rpcMethod{
+	"selection": func(params json.RawMessage) (json.RawMessage, error) {
+		var p struct {
+			Items             json.RawMessage `json:"items"`
+			HeaderText        string          `json:"headerText"`
+			FooterText        string          `json:"footerText"`
+			PerPage           int             `json:"perPage"`
+			Autocomplete      *bool           `json:"autocomplete"`
+			DefaultValue      string          `json:"defaultValue"`
+			InitialValue      string          `json:"initialValue"`
+			BackKey           string          `json:"backKey"`
+			Timeout           int             `json:"timeout"`
+			ResetTimeoutOnKey bool            `json:"resetTimeoutOnKey"`
+			OtherLabel        string          `json:"otherLabel"`
+		}
+		if err := decodeRPCParams(params, &p); err != nil {
+			return nil, err
+		}
+		return json.RawMessage(Selection(string(p.Items), p.HeaderText, p.FooterText, rpcPerPage(p.PerPage, 5), rpcBool(p.Autocomplete, true), p.DefaultValue, p.InitialValue, p.BackKey, p.Timeout, p.ResetTimeoutOnKey, p.OtherLabel)), nil
+	},
+	"multiselect": func(params json.RawMessage) (json.RawMessage, error) {
+		var p struct {
+			Items              json.RawMessage `json:"items"`
+			HeaderText         string          `json:"headerText"`
+			FooterText         string          `json:"footerText"`
+			PerPage            int             `json:"perPage"`
+			Autocomplete       *bool           `json:"autocomplete"`
+			PreselectedValues  []string        `json:"preselectedValues"`
+			InitialCursorValue string          `json:"initialCursorValue"`
+			BackKey            string          `json:"backKey"`
+			Timeout            int             `json:"timeout"`
+			ResetTimeoutOnKey  bool            `json:"resetTimeoutOnKey"`
+			OtherLabel         string          `json:"otherLabel"`
+		}
+		if err := decodeRPCParams(params, &p); err != nil {
+			return nil, err
+		}
+		preselected, _ := json.Marshal(p.PreselectedValues)
+		return json.RawMessage(Multiselect(string(p.Items), p.HeaderText, p.FooterText, rpcPerPage(p.PerPage, 5), rpcBool(p.Autocomplete, true), string(preselected), p.InitialCursorValue, p.BackKey, p.Timeout, p.ResetTimeoutOnKey, p.OtherLabel)), nil
+	},
+	"groupMultiselect": func(params json.RawMessage) (json.RawMessage, error) {
+		var p struct {
+			Items              json.RawMessage `json:"items"`
+			HeaderText         string          `json:"headerText"`
+			FooterText         string          `json:"footerText"`
+			PerPage            int             `json:"perPage"`
+			Autocomplete       *bool           `json:"autocomplete"`
+			SelectableGroups   bool            `json:"selectableGroups"`
+			PreselectedValues  []string        `json:"preselectedValues"`
+			InitialCursorValue string          `json:"initialCursorValue"`
+			GroupSpacing       int             `json:"groupSpacing"`
+			BackKey            string          `json:"backKey"`
+			Timeout            int             `json:"timeout"`
+			ResetTimeoutOnKey  bool            `json:"resetTimeoutOnKey"`
+		}
+		if err := decodeRPCParams(params, &p); err != nil {
+			return nil, err
+		}
+		preselected, _ := json.Marshal(p.PreselectedValues)
+		return json.RawMessage(GroupMultiselect(string(p.Items), p.HeaderText, p.FooterText, rpcPerPage(p.PerPage, 10), rpcBool(p.Autocomplete, true), p.SelectableGroups, string(preselected), p.InitialCursorValue, p.GroupSpacing, p.BackKey, p.Timeout, p.ResetTimeoutOnKey)), nil
+	},
+	"confirm": func(params json.RawMessage) (json.RawMessage, error) {
+		var p struct {
+			PromptText        string `json:"promptText"`
+			HeaderText        string `json:"headerText"`
+			FooterText        string `json:"footerText"`
+			DefaultValue      string `json:"defaultValue"`
+			InitialValue      string `json:"initialValue"`
+			BackKey           string `json:"backKey"`
+			Timeout           int    `json:"timeout"`
+			ResetTimeoutOnKey bool   `json:"resetTimeoutOnKey"`
+		}
+		if err := decodeRPCParams(params, &p); err != nil {
+			return nil, err
+		}
+		return json.RawMessage(Confirm(p.PromptText, p.HeaderText, p.FooterText, p.DefaultValue, p.InitialValue, p.BackKey, p.Timeout, p.ResetTimeoutOnKey)), nil
+	},
+	"input": func(params json.RawMessage) (json.RawMessage, error) {
+		var p struct {
+			PromptText        string `json:"promptText"`
+			EchoMode          string `json:"echoMode"`
+			ValidateOkPrefix  string `json:"validateOkPrefix"`
+			ValidateErrPrefix string `json:"validateErrPrefix"`
+			DefaultValue      string `json:"defaultValue"`
+			InitialValue      string `json:"initialValue"`
+			Required          bool   `json:"required"`
+			CharLimit         int    `json:"charLimit"`
+			BackKey           string `json:"backKey"`
+			Timeout           int    `json:"timeout"`
+			ResetTimeoutOnKey bool   `json:"resetTimeoutOnKey"`
+		}
+		if err := decodeRPCParams(params, &p); err != nil {
+			return nil, err
+		}
+		return json.RawMessage(Input(p.PromptText, p.EchoMode, p.ValidateOkPrefix, p.ValidateErrPrefix, p.DefaultValue, p.InitialValue, p.Required, p.CharLimit, p.BackKey, p.Timeout, p.ResetTimeoutOnKey)), nil
+	},
+	"cancelActivePrompt": func(params json.RawMessage) (json.RawMessage, error) {
+		var p struct {
+			Reason string `json:"reason"`
+		}
+		if err := decodeRPCParams(params, &p); err != nil {
+			return nil, err
+		}
+		result, _ := json.Marshal(CancelActivePrompt(p.Reason))
+		return result, nil
+	},
+}
+
+// decodeRPCParams decodes by-name params; missing params leave the defaults.
+func decodeRPCParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("invalid params: %s", err)}
+	}
+	return nil
+}
+
+func rpcPerPage(perPage, fallback int) int {
+	if perPage <= 0 {
+		return fallback
+	}
+	return perPage
+}
+
+func rpcBool(value *bool, fallback bool) bool {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
+
+// ServeRPC answers JSON-RPC 2.0 requests read from r, one message (or batch) per line,
+// and writes the responses to w. Requests are handled concurrently so that
+// "cancelActivePrompt" can close an open prompt; prompts themselves still wait for each
+// other. It returns when r is exhausted and every response has been written.
+//
+// Prompts must render apart from the transport: when they would read r or write w, e.g.
+// because no terminal can be opened and they fall back to stdin/stdout, ServeRPC returns
+// a NO_TTY PromptError without reading any request.
+func ServeRPC(r io.Reader, w io.Writer) error {
+	input, output := terminalIO()
+	if sameFile(input, r) || sameFile(output, w) {
+		return &PromptError{Code: CodeNoTTY, Message: "no terminal available to render prompts apart from the RPC transport"}
+	}
+	return serveRPC(r, w, rpcMethods)
+}
+
+// sameFile reports whether a and b are the same open file.
+func sameFile(a, b interface{}) bool {
+	fa, ok := a.(*os.File)
+	if !ok {
+		return false
+	}
+	fb, ok := b.(*os.File)
+	return ok && fa.Fd() == fb.Fd()
+}
+
+func serveRPC(r io.Reader, w io.Writer, methods map[string]rpcMethod) error {
+	var (
+		writeMu sync.Mutex
+		pending sync.WaitGroup
+	)
+	write := func(v interface{}) {
+		data, _ := json.Marshal(v)
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		w.Write(append(data, '\n'))
+	}
+
+	reader := bufio.NewReader(r)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			pending.Add(1)
+			go func(line []byte) {
+				defer pending.Done()
+				if response := handleRPCMessage(line, methods); response != nil {
+					write(response)
+				}
+			}(line)
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			pending.Wait()
+			return readErr
+		}
+	}
+	pending.Wait()
+	return nil
+}
+
+// handleRPCMessage returns the response to a request or batch, or nil when nothing must be sent back.
+func handleRPCMessage(line []byte, methods map[string]rpcMethod) interface{} {
+	if line[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(line, &batch); err != nil {
+			return rpcErrorResponse(nil, rpcParseError, "parse error")
+		}
+		if len(batch) == 0 {
+			return rpcErrorResponse(nil, rpcInvalidRequest, "invalid request: empty batch")
+		}
+		responses := []*rpcResponse{}
+		for _, raw := range batch {
+			if response := handleRPCRequest(raw, methods); response != nil {
+				responses = append(responses, response)
+			}
+		}
+		if len(responses) == 0 {
+			return nil
+		}
+		return responses
+	}
+
+	var probe interface{}
+	if err := json.Unmarshal(line, &probe); err != nil {
+		return rpcErrorResponse(nil, rpcParseError, "parse error")
+	}
+	if response := handleRPCRequest(line, methods); response != nil {
+		return response
+	}
+	return nil
+}
+
+func handleRPCRequest(raw json.RawMessage, methods map[string]rpcMethod) *rpcResponse {
+	var req rpcRequest
+	if err := json.Unmarshal(raw, &req); err != nil || req.JSONRPC != "2.0" || req.Method == "" {
+		return rpcErrorResponse(req.ID, rpcInvalidRequest, "invalid request")
+	}
+	// Requests without an id are notifications and get no response
+	notification := len(req.ID) == 0
+
+	method, ok := methods[req.Method]
+	if !ok {
+		if notification {
+			return nil
+		}
+		return rpcErrorResponse(req.ID, rpcMethodNotFound, fmt.Sprintf("method not found: %s", req.Method))
+	}
+
+	result, err := method(req.Params)
+	if notification {
+		return nil
+	}
+	if err != nil {
+		if rerr, ok := err.(*rpcError); ok {
+			return rpcErrorResponse(req.ID, rerr.Code, rerr.Message)
+		}
+		return rpcErrorResponse(req.ID, rpcInternalError, err.Error())
+	}
+	return &rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func rpcErrorResponse(id json.RawMessage, code int, message string) *rpcResponse {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
@@ -0,0 +1,154 @@
+package prompts
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+// fakeMethods stand in for the real prompts, which need a terminal.
+func fakeMethods() map[string]rpcMethod {
+	return map[string]rpcMethod{
+		"selection": func(params json.RawMessage) (json.RawMessage, error) {
+			var p struct {
+				Items []ListItem `json:"items"`
+			}
+			if err := decodeRPCParams(params, &p); err != nil {
+				return nil, err
+			}
+			result, _ := json.Marshal(&Result{SelectedIndex: p.Items[0].Value})
+			return result, nil
+		},
+		"confirm": func(params json.RawMessage) (json.RawMessage, error) {
+			result, _ := json.Marshal(&ConfirmResult{Confirmed: "true"})
+			return result, nil
+		},
+	}
+}
+
+// rpcClient writes requests to a server and reads its responses, one per line.
+type rpcClient struct {
+	t   *testing.T
+	in  *io.PipeWriter
+	out *bufio.Scanner
+}
+
+func newRPCClient(t *testing.T) (*rpcClient, chan error) {
+	reqR, reqW := io.Pipe()
+	respR, respW := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- serveRPC(reqR, respW, fakeMethods())
+		respW.Close()
+	}()
+	return &rpcClient{t: t, in: reqW, out: bufio.NewScanner(respR)}, done
+}
+
+func (c *rpcClient) send(line string) {
+	c.t.Helper()
+	if _, err := io.WriteString(c.in, line+"\n"); err != nil {
+		c.t.Fatalf("write request: %v", err)
+	}
+}
+
+func (c *rpcClient) receive(v interface{}) {
+	c.t.Helper()
+	if !c.out.Scan() {
+		c.t.Fatalf("no response: %v", c.out.Err())
+	}
+	if err := json.Unmarshal(c.out.Bytes(), v); err != nil {
+		c.t.Fatalf("decode response %q: %v", c.out.Text(), err)
+	}
+}
+
+type testResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *rpcError       `json:"error"`
+}
+
+func TestServeRPCConformance(t *testing.T) {
+	client, done := newRPCClient(t)
+
+	client.send(`{"jsonrpc":"2.0","id":1,"method":"selection","params":{"items":[{"value":"a","label":"A"}]}}`)
+	var resp testResponse
+	client.receive(&resp)
+	if resp.JSONRPC != "2.0" || string(resp.ID) != "1" || resp.Error != nil {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	var selection Result
+	if err := json.Unmarshal(resp.Result, &selection); err != nil || selection.SelectedIndex != "a" {
+		t.Fatalf("unexpected selection result %s", resp.Result)
+	}
+
+	// Notifications get no response, so the next line answers the request after it
+	client.send(`{"jsonrpc":"2.0","method":"confirm"}`)
+	client.send(`{"jsonrpc":"2.0","id":"c","method":"confirm"}`)
+	resp = testResponse{}
+	client.receive(&resp)
+	if string(resp.ID) != `"c"` || resp.Error != nil {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	errorCases := []struct {
+		request string
+		id      string
+		code    int
+	}{
+		{`{"jsonrpc":"2.0","id":2,"method":"nope"}`, "2", rpcMethodNotFound},
+		{`{"jsonrpc":"2.0","id":3,"method":"selection","params":{"items":"a"}}`, "3", rpcInvalidParams},
+		{`{"jsonrpc":"1.0","id":4,"method":"confirm"}`, "4", rpcInvalidRequest},
+		{`{"jsonrpc":`, "null", rpcParseError},
+		{`[]`, "null", rpcInvalidRequest},
+	}
+	for _, tc := range errorCases {
+		client.send(tc.request)
+		resp = testResponse{}
+		client.receive(&resp)
+		if resp.Error == nil || resp.Error.Code != tc.code || string(resp.ID) != tc.id {
+			t.Errorf("%s: got %+v, want code %d and id %s", tc.request, resp, tc.code, tc.id)
+		}
+	}
+
+	client.send(`[{"jsonrpc":"2.0","id":5,"method":"confirm"},{"jsonrpc":"2.0","method":"confirm"},{"jsonrpc":"2.0","id":6,"method":"nope"}]`)
+	var batch []testResponse
+	client.receive(&batch)
+	if len(batch) != 2 || string(batch[0].ID) != "5" || batch[0].Error != nil || batch[1].Error == nil || batch[1].Error.Code != rpcMethodNotFound {
+		t.Fatalf("unexpected batch response: %+v", batch)
+	}
+
+	client.in.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("serveRPC: %v", err)
+	}
+}
+
+func TestRPCMethodsMirrorPrompts(t *testing.T) {
+	for _, name := range []string{"selection", "multiselect", "groupMultiselect", "confirm", "input"} {
+		if _, ok := rpcMethods[name]; !ok {
+			t.Errorf("missing method %q", name)
+		}
+	}
+}
+
+func TestServeRPCRefusesToShareTheTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	// Prompts falling back to the transport would garble the responses
+	SetTerminal(r, w)
+	defer SetTerminal(nil, nil)
+
+	err = ServeRPC(r, w)
+	var perr *PromptError
+	if !errors.As(err, &perr) || perr.Code != CodeNoTTY {
+		t.Fatalf("ServeRPC = %v, want a %s error", err, CodeNoTTY)
+	}
+}
@@ -3,40 +3,56 @@ package prompts
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 	"time"
-	"unicode"
-	"unicode/utf8"
 
 	"github.com/mritd/bubbles/common"
 
 	"github.com/mritd/bubbles/selector"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"golang.org/x/term"
 )
 
 type model struct {
-	sl                    selector.Model
-	items                 []ListItem
-	ctrlCPressedOnce      bool
-	ctrlCPressTime        time.Time
-	showCancelMsg         bool
-	canceled              bool
-	autocompleteEnabled   bool
-	autocompleteBuffer    string
-	autocompleteLastInput time.Time
+	sl               selector.Model
+	items            []ListItem
+	ctrlCPressedOnce bool
+	ctrlCPressTime   time.Time
+	showCancelMsg    bool
+	canceled         bool
+	autocomplete     autocompleteState
+	backKey          string
+	wentBack         bool
+	timeout          promptTimeout
+	// startIndex is the item the cursor started on, timeoutIndex the item returned on timeout (-1 for none)
+	startIndex   int
+	timeoutIndex int
+	defaultValue string
+	other        *otherEntry
+	headerText   string
+	// parents are the menus the opened submenus were entered from, outermost first
+	parents []menuLevel
+}
+
+// menuLevel is a menu left by opening one of its submenus: its items and the index of
+// the item that was opened.
+type menuLevel struct {
+	items []ListItem
+	index int
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	return m.timeout.init()
 }
 
 type resetCancelMsg struct{}
 
 func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if handled, cmd := m.timeout.update(msg); handled {
+		return m, cmd
+	}
+
 	// Handle double Ctrl+C first - must intercept before selector sees it
 	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "ctrl+c" {
 		now := time.Now()
@@ -64,6 +80,21 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// While the "Other…" text field is shown it receives every key
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.other.typing {
+		submitted, cmd := m.other.update(keyMsg)
+		if submitted {
+			return m, tea.Quit
+		}
+		return m, cmd
+	}
+
+	// Handle back navigation before the selector or autocomplete see the key
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && isBackKey(keyMsg, m.backKey) {
+		m.wentBack = true
+		return m, tea.Quit
+	}
+
 	// By default, the prompt component will not return a "tea.Quit"
 	// message unless Ctrl+C is pressed.
 	//
@@ -71,9 +102,9 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// a "common.DONE" message when the Enter key is pressed.
 	switch msg {
 	case common.DONE:
-		// Check if current item is disabled, if so, don't allow selection
+		// Check if current item is disabled or a section, if so, don't allow selection
 		currentIndex := m.sl.Index()
-		if currentIndex < len(m.items) && m.items[currentIndex].Disabled {
+		if currentIndex < len(m.items) && m.items[currentIndex].skipped() {
 			return m, nil
 		}
 		return m, tea.Quit
@@ -82,25 +113,39 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Handle navigation - skip disabled items
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if m.handleAutocompleteKey(msg) {
+		if m.autocomplete.handleKey(msg, &m.sl, listItems(m.items)) {
 			return m, nil
 		}
 		switch msg.String() {
+		case "enter":
+			// "Other…" opens its text field instead of finishing the selector
+			if m.isOther(m.sl.Index()) {
+				m.other.open()
+				return m, nil
+			}
+			// Items with children open their submenu instead of being selected
+			if m.openSubmenu() {
+				return m, nil
+			}
+		case "right":
+			if m.openSubmenu() {
+				return m, nil
+			}
+		case "left", "esc":
+			if m.closeSubmenu() {
+				return m, nil
+			}
 		case "up", "k":
 			// Move up, skipping disabled items
+			prev := m.sl.Index()
 			_, cmd := m.sl.Update(msg)
-			// After update, check if we're on a disabled item and skip if needed
-			for m.sl.Index() < len(m.items) && m.items[m.sl.Index()].Disabled {
-				_, cmd = m.sl.Update(tea.KeyMsg{Type: tea.KeyUp})
-			}
+			skipDisabled(&m.sl, listItems(m.items), prev, -1)
 			return m, cmd
 		case "down", "j":
 			// Move down, skipping disabled items
+			prev := m.sl.Index()
 			_, cmd := m.sl.Update(msg)
-			// After update, check if we're on a disabled item and skip if needed
-			for m.sl.Index() < len(m.items) && m.items[m.sl.Index()].Disabled {
-				_, cmd = m.sl.Update(tea.KeyMsg{Type: tea.KeyDown})
-			}
+			skipDisabled(&m.sl, listItems(m.items), prev, 1)
 			return m, cmd
 		}
 	}
@@ -111,306 +156,263 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m model) View() string {
 	view := m.sl.View()
+	view += m.other.view()
+	view += m.timeout.view()
 	if m.showCancelMsg {
 		view += "\n" + common.FontColor("Press Ctrl+C again to exit", "yellow")
 	}
 	return view
 }
 
+// ItemKind marks a ListItem that only structures a list: it is skipped by navigation,
+// not numbered and never part of a result. Items without a kind are options.
+type ItemKind string
+
+const (
+	// ItemSeparator is a rule between sections, with the Label in it if there is one
+	ItemSeparator ItemKind = "separator"
+	// ItemLabel is the title of a section
+	ItemLabel ItemKind = "label"
+)
+
 type ListItem struct {
 	Value    string `json:"value"`
 	Label    string `json:"label"`
 	Hint     string `json:"hint"`
 	Disabled bool   `json:"disabled"`
+	// Kind is ItemSeparator or ItemLabel for the items that structure a list, "" for options
+	Kind ItemKind `json:"kind,omitempty"`
+	// Children turn the item of a single selection into a submenu
+	Children []ListItem `json:"children,omitempty"`
 }
 
-type Result struct {
-	SelectedIndex string `json:"selectedIndex"`
-	Error         string `json:"error"`
-}
-
-const autocompleteResetTimeout = 1500 * time.Millisecond
-
-type waitForResizeModel struct {
-	minHeight int
-	message   string
+// isSection reports whether the item is a separator or a section label rather than an option.
+func (i ListItem) isSection() bool {
+	return i.Kind == ItemSeparator || i.Kind == ItemLabel
 }
 
-func (m waitForResizeModel) Init() tea.Cmd {
-	return tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
-		return checkTerminalSizeMsg{}
-	})
+// skipped reports whether navigation passes over the item: it is disabled or a section.
+func (i ListItem) skipped() bool {
+	return i.Disabled || i.isSection()
 }
 
-type checkTerminalSizeMsg struct{}
-
-func (m waitForResizeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		if msg.Height >= m.minHeight {
-			return m, tea.Quit
-		}
-		return m, tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
-			return checkTerminalSizeMsg{}
-		})
-	case checkTerminalSizeMsg:
-		fd := int(os.Stdout.Fd())
-		_, height, err := term.GetSize(fd)
-		if err == nil && height >= m.minHeight {
-			return m, tea.Quit
-		}
-		return m, tea.Tick(time.Second/2, func(t time.Time) tea.Msg {
-			return checkTerminalSizeMsg{}
-		})
-	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" {
-			return m, tea.Quit
+// firstSelectable is the index of the first item navigation stops on, 0 when there is none.
+func firstSelectable(items []ListItem) int {
+	for i, item := range items {
+		if !item.skipped() {
+			return i
 		}
 	}
-	return m, nil
+	return 0
 }
 
-func (m waitForResizeModel) View() string {
-	fd := int(os.Stdout.Fd())
-	_, height, err := term.GetSize(fd)
-	if err != nil {
-		return fmt.Sprintf("Error checking terminal size: %s\n", err)
+// optionNumber is the 1-based number shown for the item at idx, counting options only.
+// An index past the items, such as the "Other…" entry, follows the last option.
+func optionNumber(items []ListItem, idx int) int {
+	n := 1
+	for i := 0; i < idx && i < len(items); i++ {
+		if !items[i].isSection() {
+			n++
+		}
 	}
-	return fmt.Sprintf("\n%s\n\nCurrent height: %d | Required: %d\n\nPlease resize your terminal window to continue...\n", m.message, height, m.minHeight)
+	return n
 }
 
-func waitForTerminalResize(minHeight int, message string) error {
-	m := waitForResizeModel{
-		minHeight: minHeight,
-		message:   message,
+// sectionLine renders a separator or a section label of a list.
+func sectionLine(item ListItem) string {
+	if item.Kind == ItemLabel {
+		return common.FontColor(item.Label, selector.ColorHeader)
 	}
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	err := p.Start()
-	if err != nil {
-		return err
+	if item.Label != "" {
+		return common.FontColor("── "+item.Label+" ──", "240")
 	}
+	return common.FontColor("────────────", "240")
+}
 
-	// Block until terminal is large enough
-	fd := int(os.Stdout.Fd())
-	for {
-		_, height, err := term.GetSize(fd)
-		if err == nil && height >= minHeight {
-			break
-		}
-		time.Sleep(time.Second / 2)
-	}
-	return nil
+type Result struct {
+	// SelectedIndex is the index of the answer in the menu it was chosen from
+	SelectedIndex string `json:"selectedIndex"`
+	// Path holds the values from the top-level item down to the answer
+	Path []string `json:"path,omitempty"`
+	// CustomValue is the text typed for the "Other…" entry; Custom marks such an answer
+	CustomValue string `json:"customValue,omitempty"`
+	Custom      bool   `json:"custom,omitempty"`
+	resultMeta
 }
 
-func (m *model) handleAutocompleteKey(msg tea.KeyMsg) bool {
-	if !m.autocompleteEnabled || len(m.items) == 0 {
-		return false
-	}
-	switch msg.Type {
-	case tea.KeyRunes:
-		if len(msg.Runes) == 0 {
-			return false
-		}
-		if !m.autocompleteLastInput.IsZero() && time.Since(m.autocompleteLastInput) > autocompleteResetTimeout {
-			m.autocompleteBuffer = ""
-		}
-		r := msg.Runes[0]
-		if unicode.IsSpace(r) {
-			return false
-		}
-		if unicode.IsDigit(r) && m.autocompleteBuffer == "" {
-			return false
-		}
-		if !isAutocompleteRune(r) {
-			return false
-		}
-		m.autocompleteLastInput = time.Now()
-		m.autocompleteBuffer += strings.ToLower(string(msg.Runes))
-		m.focusAutocompleteMatch()
-		return true
-	case tea.KeyBackspace:
-		if m.autocompleteBuffer == "" {
-			return false
-		}
-		m.autocompleteBuffer = trimLastRune(m.autocompleteBuffer)
-		m.autocompleteLastInput = time.Now()
-		if m.autocompleteBuffer == "" {
-			return true
-		}
-		m.focusAutocompleteMatch()
-		return true
-	case tea.KeyEsc:
-		if m.autocompleteBuffer == "" {
-			return false
-		}
-		m.autocompleteBuffer = ""
-		m.autocompleteLastInput = time.Time{}
-		return true
-	default:
+// isOther reports whether idx is the "Other…" entry, which only the top-level menu has.
+func (m *model) isOther(idx int) bool {
+	return len(m.parents) == 0 && m.other.is(idx)
+}
+
+// openSubmenu shows the children of the item under the cursor. It reports false when the
+// item has no children or is disabled.
+func (m *model) openSubmenu() bool {
+	idx := m.sl.Index()
+	if idx < 0 || idx >= len(m.items) || m.items[idx].skipped() || len(m.items[idx].Children) == 0 {
 		return false
 	}
+	m.parents = append(m.parents, menuLevel{items: m.items, index: idx})
+	children := m.items[idx].Children
+	m.showMenu(children, firstSelectable(children))
+	return true
 }
 
-func (m *model) focusAutocompleteMatch() {
-	if m.autocompleteBuffer == "" {
-		return
-	}
-	idx := m.findAutocompleteMatch()
-	if idx >= 0 {
-		m.moveSelectorTo(idx)
+// closeSubmenu returns to the parent menu with the cursor on the submenu it left. It
+// reports false in the top-level menu.
+func (m *model) closeSubmenu() bool {
+	if len(m.parents) == 0 {
+		return false
 	}
+	parent := m.parents[len(m.parents)-1]
+	m.parents = m.parents[:len(m.parents)-1]
+	m.showMenu(parent.items, parent.index)
+	return true
 }
 
-func (m *model) findAutocompleteMatch() int {
-	if m.autocompleteBuffer == "" || len(m.items) == 0 {
-		return -1
-	}
-	query := strings.ToLower(m.autocompleteBuffer)
-	total := len(m.items)
-	start := m.sl.Index()
-	for offset := 0; offset < total; offset++ {
-		idx := (start + offset) % total
-		if idx < 0 || idx >= total {
-			continue
-		}
-		item := m.items[idx]
-		if item.Disabled {
-			continue
-		}
-		label := strings.ToLower(item.Label)
-		hint := strings.ToLower(item.Hint)
-		value := strings.ToLower(item.Value)
-		if strings.Contains(label, query) || (item.Hint != "" && strings.Contains(hint, query)) || strings.Contains(value, query) {
-			return idx
-		}
-	}
-	return -1
+// showMenu replaces the items of the selector, keeping its templates, and puts the
+// cursor on cursor.
+func (m *model) showMenu(items []ListItem, cursor int) {
+	data := []interface{}{}
+	for _, val := range items {
+		data = append(data, val)
+	}
+	if len(m.parents) == 0 && m.other.enabled() {
+		data = append(data, ListItem{Label: m.other.label})
+	}
+	m.items = items
+	m.autocomplete.buffer = ""
+	m.sl = selector.Model{
+		Data:           data,
+		PerPage:        m.sl.PerPage,
+		HeaderFunc:     m.sl.HeaderFunc,
+		SelectedFunc:   m.sl.SelectedFunc,
+		UnSelectedFunc: m.sl.UnSelectedFunc,
+		FooterFunc:     m.sl.FooterFunc,
+		FinishedFunc:   m.sl.FinishedFunc,
+	}
+	// The first update only initializes the selector
+	m.sl.Update(tea.KeyMsg{Type: tea.KeyDown})
+	moveSelectorTo(&m.sl, listItems(m.items), cursor)
 }
 
-func (m *model) moveSelectorTo(target int) {
-	if target < 0 || target >= len(m.items) {
-		return
-	}
-	guard := 0
-	for m.sl.Index() != target && guard < len(m.items)*2 {
-		if m.sl.Index() < target {
-			if !m.stepSelector(1) {
-				break
-			}
-		} else {
-			if !m.stepSelector(-1) {
-				break
-			}
+// breadcrumb is the labels of the opened submenus, "" in the top-level menu.
+func (m *model) breadcrumb() string {
+	labels := make([]string, 0, len(m.parents))
+	for _, level := range m.parents {
+		item := level.items[level.index]
+		label := item.Label
+		if label == "" {
+			label = item.Value
 		}
-		guard++
+		labels = append(labels, label)
 	}
+	return strings.Join(labels, " › ")
 }
 
-func (m *model) stepSelector(direction int) bool {
-	key := tea.KeyMsg{Type: tea.KeyDown}
-	if direction < 0 {
-		key = tea.KeyMsg{Type: tea.KeyUp}
-	}
-	prev := m.sl.Index()
-	m.sl.Update(key)
-	if m.sl.Index() == prev {
-		return false
+// valuePath is the path of values from the top-level menu down to the item at index of
+// the current menu. On timeout index refers to the top-level menu.
+func (m *model) valuePath(index int) []string {
+	if index < 0 {
+		return nil
 	}
-	for m.sl.Index() < len(m.items) && m.items[m.sl.Index()].Disabled {
-		prev = m.sl.Index()
-		m.sl.Update(key)
-		if m.sl.Index() == prev {
-			return false
+	if m.timeout.expired {
+		root := m.items
+		if len(m.parents) > 0 {
+			root = m.parents[0].items
 		}
+		return []string{root[index].Value}
 	}
-	return true
+	path := make([]string, 0, len(m.parents)+1)
+	for _, level := range m.parents {
+		path = append(path, level.items[level.index].Value)
+	}
+	return append(path, m.items[index].Value)
 }
 
-func trimLastRune(value string) string {
-	if value == "" {
-		return value
-	}
-	_, size := utf8.DecodeLastRuneInString(value)
-	if size <= 0 || size > len(value) {
-		return ""
-	}
-	return value[:len(value)-size]
+func Selection(jsonData, headerText, footerText string, perPage int, autocomplete bool, defaultValue, initialValue, backKey string, timeout int, resetTimeoutOnKey bool, otherLabel string) string {
+	return selection(newPromptSession(), jsonData, headerText, footerText, perPage, autocomplete, defaultValue, initialValue, backKey, timeout, resetTimeoutOnKey, otherLabel)
 }
 
-func isAutocompleteRune(r rune) bool {
-	if unicode.IsLetter(r) {
-		return true
-	}
-	if unicode.IsDigit(r) {
-		return true
-	}
-	switch r {
-	case '-', '_', '.', '/', '+', '#':
-		return true
-	default:
-		return false
+func selection(s *promptSession, jsonData, headerText, footerText string, perPage int, autocomplete bool, defaultValue, initialValue, backKey string, timeout int, resetTimeoutOnKey bool, otherLabel string) string {
+	var items []ListItem
+	if err := parseJSONArg(jsonData, "items", &items); err != nil {
+		result, _ := json.Marshal(&Result{SelectedIndex: "", resultMeta: s.meta(err)})
+		return string(result)
 	}
+	o := SelectOptions{
+		PromptOptions: PromptOptions{
+			Header:            headerText,
+			Footer:            footerText,
+			BackKey:           backKey,
+			Timeout:           time.Duration(timeout) * time.Second,
+			ResetTimeoutOnKey: resetTimeoutOnKey,
+		},
+		PerPage:      perPage,
+		Autocomplete: autocomplete,
+		Default:      defaultValue,
+		Initial:      initialValue,
+		other:        otherLabel,
+	}
+	index, path, custom, err := o.run(s, items)
+	selectedIndex := ""
+	if index >= 0 {
+		selectedIndex = strconv.Itoa(index)
+	}
+	result, _ := json.Marshal(&Result{
+		SelectedIndex: selectedIndex,
+		Path:          path,
+		CustomValue:   custom,
+		Custom:        custom != "",
+		resultMeta:    s.meta(err),
+	})
+	return string(result)
 }
 
-func formatAutocompleteFooter(base, buffer string) string {
-	hint := "Type to search"
-	if buffer != "" {
-		hint = fmt.Sprintf("Filter: %s", buffer)
+// run shows the selection and returns the index of the answer in its menu, the path of
+// values leading to it and the text typed for "Other…".
+func (o SelectOptions) run(s *promptSession, item []ListItem) (int, []string, string, error) {
+	if err := o.validate(item); err != nil {
+		return -1, nil, "", err
 	}
-	base = strings.TrimSpace(base)
-	if base == "" {
-		return hint
+	perPage := o.PerPage
+	if perPage <= 0 {
+		perPage = 5
 	}
-	return fmt.Sprintf("%s  |  %s", base, hint)
-}
 
-func Selection(jsonData, headerText, footerText string, perPage int, autocomplete bool, defaultValue, initialValue string) string {
 	// Minimum height: header (1) + perPage items + footer (1) + buffer (2) = perPage + 4
 	minTerminalHeight := perPage + 4
 	if minTerminalHeight < 5 {
 		minTerminalHeight = 5
 	}
 
-	var err error
-
-	if shouldValidateTerminalSize() {
-		height, sizeErr := getTerminalHeight()
-		if sizeErr != nil {
-			result, _ := json.Marshal(&Result{
-				SelectedIndex: "",
-				Error:         fmt.Sprintf("failed to get terminal size: %s", sizeErr),
-			})
-			return string(result)
-		}
-
-		if height < minTerminalHeight {
-			// Wait for user to resize terminal instead of returning error
-			waitMessage := fmt.Sprintf("⚠️  Terminal height too small!\n   Current: %d lines | Required: %d lines (for perPage=%d)", height, minTerminalHeight, perPage)
-			err = waitForTerminalResize(minTerminalHeight, waitMessage)
-			if err != nil {
-				result, _ := json.Marshal(&Result{
-					SelectedIndex: "",
-					Error:         fmt.Sprintf("failed to wait for terminal resize: %s", err),
-				})
-				return string(result)
-			}
-		}
+	m := o.newModel(item, perPage)
+	err := s.run(m, minTerminalHeight)
+	if err != nil {
+		return -1, nil, "", err
 	}
-
-	var item []ListItem
-	json.Unmarshal([]byte(jsonData), &item)
-	data := []interface{}{}
-	for _, val := range item {
-		data = append(data, ListItem{Value: val.Value, Label: val.Label, Hint: val.Hint, Disabled: val.Disabled})
+	if s.cancelled() {
+		return -1, nil, "", ErrCancelled
 	}
+	index, custom, err := m.outcome()
+	return index, m.valuePath(index), custom, err
+}
 
-	// Determine start index based on initialValue or defaultValue
-	startIndex := 0
+// newModel builds the selection model for item, showing perPage items at a time.
+func (o SelectOptions) newModel(item []ListItem, perPage int) *model {
+	headerText, footerText := o.Header, o.Footer
+	defaultValue, initialValue := o.Default, o.Initial
+
+	other := newOtherEntry(o.other, len(item))
+
+	// Determine start index based on initialValue or defaultValue, otherwise start on
+	// the first item that can be selected
+	startIndex := firstSelectable(item)
 
 	// If initialValue is provided, find the matching item
 	if initialValue != "" {
 		for i, it := range item {
-			if it.Value == initialValue && !it.Disabled {
+			if it.Value == initialValue && !it.skipped() {
 				startIndex = i
 				break
 			}
@@ -418,127 +420,154 @@ func Selection(jsonData, headerText, footerText string, perPage int, autocomplet
 	} else if defaultValue != "" {
 		// If no initialValue but defaultValue is provided, use it
 		for i, it := range item {
-			if it.Value == defaultValue && !it.Disabled {
+			if it.Value == defaultValue && !it.skipped() {
 				startIndex = i
 				break
 			}
 		}
-	} else {
-		// Otherwise, find first non-disabled item
-		for startIndex < len(item) && item[startIndex].Disabled {
-			startIndex++
+	}
+
+	// On timeout, resolve with defaultValue, falling back to initialValue
+	timeoutIndex := -1
+	for _, fallback := range []string{defaultValue, initialValue} {
+		if fallback == "" || timeoutIndex >= 0 {
+			continue
 		}
-		if startIndex >= len(item) {
-			startIndex = 0
+		for i, it := range item {
+			if it.Value == fallback && !it.skipped() {
+				timeoutIndex = i
+				break
+			}
 		}
 	}
 
-	sl := selector.Model{
-		Data:       data,
-		PerPage:    perPage,
-		HeaderFunc: selector.DefaultHeaderFuncWithAppend(headerText),
+	m := &model{
+		ctrlCPressedOnce: false,
+		showCancelMsg:    false,
+		canceled:         false,
+		autocomplete:     autocompleteState{enabled: o.Autocomplete},
+		backKey:          o.BackKey,
+		timeout:          newPromptTimeout(o.timeoutSeconds(), o.ResetTimeoutOnKey),
+		startIndex:       startIndex,
+		timeoutIndex:     timeoutIndex,
+		defaultValue:     defaultValue,
+		other:            other,
+		headerText:       headerText,
+	}
+	m.timeout.hasDefault = timeoutIndex >= 0
+
+	// The templates read the current menu from m, so they keep working in submenus
+	m.sl = selector.Model{
+		PerPage: perPage,
+		HeaderFunc: func(sl selector.Model, obj interface{}, gdIndex int) string {
+			header := m.headerText
+			if crumb := m.breadcrumb(); crumb != "" {
+				header += "\n" + crumb
+			}
+			return selector.DefaultHeaderFuncWithAppend(header)(sl, obj, gdIndex)
+		},
 		SelectedFunc: func(sl selector.Model, obj interface{}, gdIndex int) string {
 			t := obj.(ListItem)
+			if t.isSection() {
+				return sectionLine(t)
+			}
+			n := optionNumber(m.items, gdIndex)
+			if m.isOther(gdIndex) {
+				t.Label = m.other.itemLabel()
+			}
+			if len(t.Children) > 0 {
+				t.Label += " ›"
+			}
 			disabled := t.Disabled
-			if gdIndex < len(item) {
-				disabled = item[gdIndex].Disabled
+			if gdIndex < len(m.items) {
+				disabled = m.items[gdIndex].Disabled
 			}
 			if disabled {
 				if t.Hint != "" {
-					return common.FontColor(fmt.Sprintf("[%d] %s (%s) (disabled)", gdIndex+1, t.Label, t.Hint), "240")
+					return common.FontColor(fmt.Sprintf("[%d] %s (%s) (disabled)", n, t.Label, t.Hint), "240")
 				}
-				return common.FontColor(fmt.Sprintf("[%d] %s (disabled)", gdIndex+1, t.Label), "240")
+				return common.FontColor(fmt.Sprintf("[%d] %s (disabled)", n, t.Label), "240")
 			}
 			if t.Hint != "" {
-				return common.FontColor(fmt.Sprintf("[%d] %s (%s)", gdIndex+1, t.Label, t.Hint), selector.ColorSelected)
+				return common.FontColor(fmt.Sprintf("[%d] %s (%s)", n, t.Label, t.Hint), selector.ColorSelected)
 			}
-			return common.FontColor(fmt.Sprintf("[%d] %s", gdIndex+1, t.Label), selector.ColorSelected)
+			return common.FontColor(fmt.Sprintf("[%d] %s", n, t.Label), selector.ColorSelected)
 		},
 		UnSelectedFunc: func(sl selector.Model, obj interface{}, gdIndex int) string {
 			t := obj.(ListItem)
+			if t.isSection() {
+				return sectionLine(t)
+			}
+			n := optionNumber(m.items, gdIndex)
+			if m.isOther(gdIndex) {
+				t.Label = m.other.itemLabel()
+			}
+			if len(t.Children) > 0 {
+				t.Label += " ›"
+			}
 			disabled := t.Disabled
-			if gdIndex < len(item) {
-				disabled = item[gdIndex].Disabled
+			if gdIndex < len(m.items) {
+				disabled = m.items[gdIndex].Disabled
 			}
 			if disabled {
-				return common.FontColor(fmt.Sprintf(" %d. %s (disabled)", gdIndex+1, t.Label), "240")
+				return common.FontColor(fmt.Sprintf(" %d. %s (disabled)", n, t.Label), "240")
 			}
-			return common.FontColor(fmt.Sprintf(" %d. %s", gdIndex+1, t.Label), selector.ColorUnSelected)
+			return common.FontColor(fmt.Sprintf(" %d. %s", n, t.Label), selector.ColorUnSelected)
 		},
 		FooterFunc: func(sl selector.Model, obj interface{}, gdIndex int) string {
-			return common.FontColor(footerText, selector.ColorFooter)
+			if !m.autocomplete.enabled {
+				return common.FontColor(footerText, selector.ColorFooter)
+			}
+			return common.FontColor(formatAutocompleteFooter(footerText, m.autocomplete.buffer), selector.ColorFooter)
 		},
 		FinishedFunc: func(s interface{}) string {
 			return ""
 		},
 	}
+	m.showMenu(item, startIndex)
+	return m
+}
 
-	m := &model{
-		items:               item,
-		ctrlCPressedOnce:    false,
-		showCancelMsg:       false,
-		canceled:            false,
-		autocompleteEnabled: autocomplete,
-		autocompleteBuffer:  "",
-		sl:                  sl,
-	}
-
-	m.sl.FooterFunc = func(sl selector.Model, obj interface{}, gdIndex int) string {
-		if !m.autocompleteEnabled {
-			return common.FontColor(footerText, selector.ColorFooter)
+// outcome is the answer of the selection once its program has quit.
+func (m *model) outcome() (int, string, error) {
+	if m.timeout.expired {
+		if m.timeoutIndex < 0 {
+			return -1, "", ErrTimeout
 		}
-		return common.FontColor(formatAutocompleteFooter(footerText, m.autocompleteBuffer), selector.ColorFooter)
+		return m.timeoutIndex, "", nil
 	}
-
-	// Set initial index to first non-disabled item
-	// Add +1 to account for the initial position (selector starts at 0, we need to move to startIndex)
-	for i := 0; i < startIndex+1; i++ {
-		m.sl.Update(tea.KeyMsg{Type: tea.KeyDown})
-	}
-
-	p := tea.NewProgram(m)
-	err = p.Start()
-	if err != nil {
-		result, _ := json.Marshal(&Result{
-			SelectedIndex: "",
-			Error:         fmt.Sprintf("%s", err),
-		})
-		return string(result)
-	}
-	if !m.canceled && !m.sl.Canceled() {
-		selectedIndex := m.sl.Index()
-		// Ensure we didn't select a disabled item
-		if selectedIndex < len(m.items) && m.items[selectedIndex].Disabled {
-			result, _ := json.Marshal(&Result{
-				SelectedIndex: "",
-				Error:         "Cannot select disabled item",
-			})
-			return string(result)
+	if m.wentBack {
+		// Report the item under the cursor as the partial value
+		if idx := m.sl.Index(); idx < len(m.items) && !m.items[idx].skipped() {
+			return idx, "", ErrBack
 		}
-		// If user didn't change selection from initial position and defaultValue is provided, use it
-		if defaultValue != "" && selectedIndex == startIndex {
-			selectedValue := m.items[selectedIndex].Value
-			// If defaultValue is different from what's currently selected, find and use it
-			if defaultValue != selectedValue {
-				// Find defaultValue in items
-				for i, it := range m.items {
-					if it.Value == defaultValue && !it.Disabled {
-						selectedIndex = i
-						break
-					}
+		return -1, "", ErrBack
+	}
+	if m.canceled || m.sl.Canceled() {
+		return -1, "", ErrCancelled
+	}
+	if m.other.value != "" {
+		// The text typed for "Other…" is the answer
+		return -1, m.other.value, nil
+	}
+	selectedIndex := m.sl.Index()
+	// Ensure we didn't select a disabled item, or nothing at all
+	if selectedIndex < 0 || selectedIndex >= len(m.items) || m.items[selectedIndex].skipped() {
+		return -1, "", &PromptError{Code: CodeInvalidInput, Message: "Cannot select disabled item"}
+	}
+	// If user didn't change selection from initial position and defaultValue is provided, use it
+	if m.defaultValue != "" && selectedIndex == m.startIndex && len(m.parents) == 0 {
+		selectedValue := m.items[selectedIndex].Value
+		// If defaultValue is different from what's currently selected, find and use it
+		if m.defaultValue != selectedValue {
+			// Find defaultValue in items
+			for i, it := range m.items {
+				if it.Value == m.defaultValue && !it.skipped() {
+					selectedIndex = i
+					break
 				}
 			}
 		}
-		result, _ := json.Marshal(&Result{
-			SelectedIndex: strconv.Itoa(selectedIndex),
-			Error:         "",
-		})
-		return string(result)
-	} else {
-		result, _ := json.Marshal(&Result{
-			SelectedIndex: "",
-			Error:         "Cancelled",
-		})
-		return string(result)
 	}
+	return selectedIndex, "", nil
 }
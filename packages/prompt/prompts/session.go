@@ -0,0 +1,197 @@
+package prompts
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// resultMeta holds the fields shared by every prompt result, see the error codes for the contract.
+type resultMeta struct {
+	Error   string `json:"error"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+	Details string `json:"details,omitempty"`
+	// Reason explains a "Cancelled" error when the prompt was closed from outside
+	Reason string `json:"reason,omitempty"`
+	// WaitedMs is how long the prompt waited for another prompt to release the terminal
+	WaitedMs int64 `json:"waitedMs"`
+}
+
+// promptSession carries the state shared by one prompt invocation, independent of the
+// prompt type. It lets a prompt be cancelled from another goroutine while it is running.
+type promptSession struct {
+	mu           sync.Mutex
+	cancelCh     chan struct{}
+	isCanceled   bool
+	cancelReason string
+	// cause is the context error when the session was cancelled by its context
+	cause  error
+	waited time.Duration
+}
+
+// activeSessions are the sessions whose program is currently running.
+var activeSessions = struct {
+	sync.Mutex
+	sessions map[*promptSession]struct{}
+}{sessions: make(map[*promptSession]struct{})}
+
+func newPromptSession() *promptSession {
+	return &promptSession{cancelCh: make(chan struct{})}
+}
+
+// cancel ends the running (or next) program of the session. It is safe to call more
+// than once, only the first reason is kept.
+func (s *promptSession) cancel(reason string) {
+	s.cancelCause(reason, nil)
+}
+
+// cancelCause cancels the session like cancel and records why, unless it was already cancelled.
+func (s *promptSession) cancelCause(reason string, cause error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.isCanceled {
+		return
+	}
+	s.isCanceled = true
+	s.cancelReason = reason
+	s.cause = cause
+	close(s.cancelCh)
+}
+
+// watch cancels the session once ctx is done. The returned function stops watching and
+// maps the error of the prompt: a prompt closed by ctx reports ctx.Err() instead of ErrCancelled.
+func (s *promptSession) watch(ctx context.Context) func(err error) error {
+	stopped := make(chan struct{})
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				s.cancelCause(ctx.Err().Error(), ctx.Err())
+			case <-stopped:
+			}
+		}()
+	}
+	return func(err error) error {
+		close(stopped)
+		s.mu.Lock()
+		cause := s.cause
+		s.mu.Unlock()
+		if cause != nil && errors.Is(err, ErrCancelled) {
+			return cause
+		}
+		return err
+	}
+}
+
+// cancelled reports whether the session was cancelled from outside the prompt.
+func (s *promptSession) cancelled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.isCanceled
+}
+
+// meta returns the shared result fields of the session for a prompt that ended with err.
+func (s *promptSession) meta(err error) resultMeta {
+	text, code, message, details := errorFields(err)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return resultMeta{
+		Error:    text,
+		Code:     code,
+		Message:  message,
+		Details:  details,
+		Reason:   s.cancelReason,
+		WaitedMs: s.waited.Milliseconds(),
+	}
+}
+
+// run starts a program for m once the prompt lock is available and blocks until it
+// quits or the session is cancelled. The terminal must be at least minHeight lines tall,
+// the user is asked to resize it otherwise.
+func (s *promptSession) run(m tea.Model, minHeight int, opts ...tea.ProgramOption) error {
+	acquired, err := s.acquire()
+	if err != nil || !acquired {
+		return err
+	}
+	defer s.release()
+	// Spinners and progress bars give the terminal to the prompt until the lock is free again
+	live.pause()
+
+	activeSessions.Lock()
+	activeSessions.sessions[s] = struct{}{}
+	activeSessions.Unlock()
+	defer func() {
+		activeSessions.Lock()
+		delete(activeSessions.sessions, s)
+		activeSessions.Unlock()
+	}()
+
+	if err := s.waitForHeight(minHeight); err != nil || s.cancelled() {
+		return err
+	}
+	return s.start(m, opts...)
+}
+
+// start runs a program for m that quits when the session is cancelled.
+func (s *promptSession) start(m tea.Model, opts ...tea.ProgramOption) error {
+	done := make(chan struct{})
+	defer close(done)
+
+	terminalOpts, terminalDone := terminalProgramOptions()
+	defer terminalDone()
+	p := tea.NewProgram(&sessionModel{Model: m, session: s, done: done}, append(terminalOpts, opts...)...)
+	return p.Start()
+}
+
+// CancelActivePrompt closes every prompt that is currently open, restoring the terminal.
+// The prompts resolve with a "Cancelled" error carrying reason. It reports whether any
+// prompt was open.
+func CancelActivePrompt(reason string) bool {
+	activeSessions.Lock()
+	sessions := make([]*promptSession, 0, len(activeSessions.sessions))
+	for s := range activeSessions.sessions {
+		sessions = append(sessions, s)
+	}
+	activeSessions.Unlock()
+
+	for _, s := range sessions {
+		s.cancel(reason)
+	}
+	return len(sessions) > 0
+}
+
+type sessionCancelMsg struct{}
+
+// sessionModel wraps a prompt model and quits it when its session is cancelled.
+type sessionModel struct {
+	tea.Model
+	session *promptSession
+	done    chan struct{}
+}
+
+func (m *sessionModel) Init() tea.Cmd {
+	return tea.Batch(m.Model.Init(), m.listen)
+}
+
+// listen waits for a cancellation; it returns nil once the program has exited so the goroutine does not leak.
+func (m *sessionModel) listen() tea.Msg {
+	select {
+	case <-m.session.cancelCh:
+		return sessionCancelMsg{}
+	case <-m.done:
+		return nil
+	}
+}
+
+func (m *sessionModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(sessionCancelMsg); ok {
+		return m, tea.Quit
+	}
+	var cmd tea.Cmd
+	m.Model, cmd = m.Model.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,529 @@
+package prompts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mritd/bubbles/common"
+
+	"github.com/mritd/bubbles/selector"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// streamSource holds the items of a streaming selection. The caller mutates it through
+// the Stream* functions while the prompt is open; notify wakes the prompt up.
+type streamSource struct {
+	mu       sync.Mutex
+	items    []ListItem
+	complete bool
+	closed   bool
+	notify   chan struct{}
+}
+
+func newStreamSource(items []ListItem) *streamSource {
+	return &streamSource{
+		items:  items,
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// changed wakes up the prompt without blocking; several changes collapse into one wake-up.
+func (src *streamSource) changed() {
+	select {
+	case src.notify <- struct{}{}:
+	default:
+	}
+}
+
+// snapshot returns a copy of the current items and whether the stream is complete.
+func (src *streamSource) snapshot() ([]ListItem, bool) {
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	items := make([]ListItem, len(src.items))
+	copy(items, src.items)
+	return items, src.complete
+}
+
+var streamSources = struct {
+	sync.Mutex
+	sources map[int]*streamSource
+}{sources: make(map[int]*streamSource)}
+
+// mutateStream applies fn to the source of a streaming selection. It returns false
+// for unknown handles and for prompts that are already closed.
+func mutateStream(id int, fn func(src *streamSource) bool) bool {
+	streamSources.Lock()
+	src, ok := streamSources.sources[id]
+	streamSources.Unlock()
+	if !ok {
+		return false
+	}
+
+	src.mu.Lock()
+	if src.closed {
+		src.mu.Unlock()
+		return false
+	}
+	ok = fn(src)
+	src.mu.Unlock()
+	if ok {
+		src.changed()
+	}
+	return ok
+}
+
+// unregister removes the source from streamSources once its prompt is closed.
+func (src *streamSource) unregister() {
+	streamSources.Lock()
+	defer streamSources.Unlock()
+	for id, registered := range streamSources.sources {
+		if registered == src {
+			delete(streamSources.sources, id)
+			return
+		}
+	}
+}
+
+// StreamAppend adds items (a JSON array of ListItem) to an open streaming selection.
+func StreamAppend(id int, jsonData string) bool {
+	var items []ListItem
+	if err := json.Unmarshal([]byte(jsonData), &items); err != nil {
+		return false
+	}
+	return mutateStream(id, func(src *streamSource) bool {
+		src.items = append(src.items, items...)
+		return true
+	})
+}
+
+// StreamUpdate replaces the item (a JSON ListItem) with the same value.
+func StreamUpdate(id int, jsonData string) bool {
+	var item ListItem
+	if err := json.Unmarshal([]byte(jsonData), &item); err != nil {
+		return false
+	}
+	return mutateStream(id, func(src *streamSource) bool {
+		for i := range src.items {
+			if src.items[i].Value == item.Value {
+				src.items[i] = item
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// StreamRemove removes the item with the given value.
+func StreamRemove(id int, value string) bool {
+	return mutateStream(id, func(src *streamSource) bool {
+		for i := range src.items {
+			if src.items[i].Value == value {
+				src.items = append(src.items[:i], src.items[i+1:]...)
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// StreamComplete marks the stream as complete, which hides the loading indicator.
+func StreamComplete(id int) bool {
+	return mutateStream(id, func(src *streamSource) bool {
+		src.complete = true
+		return true
+	})
+}
+
+type StreamResult struct {
+	Value string `json:"value"`
+	resultMeta
+}
+
+type streamChangedMsg struct{}
+
+type streamLoadingTickMsg struct{}
+
+type streamModel struct {
+	source           *streamSource
+	done             chan struct{}
+	items            []ListItem
+	complete         bool
+	cursor           int
+	offset           int
+	perPage          int
+	headerText       string
+	footerText       string
+	initialValue     string
+	defaultValue     string
+	moved            bool
+	frame            int
+	chosen           bool
+	ctrlCPressedOnce bool
+	ctrlCPressTime   time.Time
+	showCancelMsg    bool
+	canceled         bool
+	backKey          string
+	wentBack         bool
+	timeout          promptTimeout
+}
+
+func (m *streamModel) Init() tea.Cmd {
+	return tea.Batch(m.timeout.init(), m.listen, m.loadingTick())
+}
+
+// listen waits for the next change of the source; it returns nil once the prompt is done.
+func (m *streamModel) listen() tea.Msg {
+	select {
+	case <-m.source.notify:
+		return streamChangedMsg{}
+	case <-m.done:
+		return nil
+	}
+}
+
+// loadingTick schedules the next frame of the loading indicator. Only the chain started
+// in Init re-arms it, so changes of the source do not add timers.
+func (m *streamModel) loadingTick() tea.Cmd {
+	if m.complete {
+		return nil
+	}
+	return tea.Tick(liveFrameInterval, func(time.Time) tea.Msg {
+		return streamLoadingTickMsg{}
+	})
+}
+
+type streamResetCancelMsg struct{}
+
+func (m *streamModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if handled, cmd := m.timeout.update(msg); handled {
+		return m, cmd
+	}
+
+	switch msg.(type) {
+	case streamChangedMsg:
+		m.refresh()
+		return m, m.listen
+	case streamLoadingTickMsg:
+		m.frame++
+		return m, m.loadingTick()
+	}
+
+	// Handle double Ctrl+C first
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "ctrl+c" {
+		now := time.Now()
+		if m.ctrlCPressedOnce && now.Sub(m.ctrlCPressTime) < 2*time.Second {
+			// Second Ctrl+C within 2 seconds - actually cancel
+			m.canceled = true
+			return m, tea.Quit
+		}
+		// First Ctrl+C - show message and set timer
+		m.ctrlCPressedOnce = true
+		m.ctrlCPressTime = now
+		m.showCancelMsg = true
+		// Reset after 2 seconds
+		return m, tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
+			return streamResetCancelMsg{}
+		})
+	}
+
+	// Handle reset message
+	if _, ok := msg.(streamResetCancelMsg); ok {
+		// Reset cancel state after timeout
+		m.ctrlCPressedOnce = false
+		m.showCancelMsg = false
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if isBackKey(keyMsg, m.backKey) {
+		m.wentBack = true
+		return m, tea.Quit
+	}
+
+	switch keyMsg.String() {
+	case "enter":
+		if m.cursor < len(m.items) && !m.items[m.cursor].Disabled {
+			m.chosen = true
+			return m, tea.Quit
+		}
+	case "up", "k":
+		m.moveCursor(-1)
+	case "down", "j":
+		m.moveCursor(1)
+	}
+	return m, nil
+}
+
+// moveCursor moves to the next enabled item in the given direction.
+func (m *streamModel) moveCursor(direction int) {
+	for i := m.cursor + direction; i >= 0 && i < len(m.items); i += direction {
+		if !m.items[i].Disabled {
+			m.cursor = i
+			m.moved = true
+			m.scrollToCursor()
+			return
+		}
+	}
+}
+
+func (m *streamModel) scrollToCursor() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.perPage {
+		m.offset = m.cursor - m.perPage + 1
+	}
+	if maxOffset := len(m.items) - m.perPage; m.offset > maxOffset {
+		m.offset = maxOffset
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
+// refresh takes the latest items from the source, keeping the cursor on the same value.
+func (m *streamModel) refresh() {
+	current := ""
+	if m.cursor < len(m.items) {
+		current = m.items[m.cursor].Value
+	}
+	m.items, m.complete = m.source.snapshot()
+
+	target := -1
+	// Until the user moves, follow the initial value as soon as it arrives
+	if !m.moved && m.initialValue != "" {
+		current = m.initialValue
+	}
+	for i, item := range m.items {
+		if item.Value == current && !item.Disabled {
+			target = i
+			break
+		}
+	}
+	if target < 0 {
+		// The item went away (or is disabled now), stay at the same position
+		target = m.cursor
+		if target >= len(m.items) {
+			target = len(m.items) - 1
+		}
+		for target >= 0 && m.items[target].Disabled {
+			target--
+		}
+		if target < 0 {
+			target = m.firstEnabled()
+		}
+	}
+	if target < 0 {
+		target = 0
+	}
+	m.cursor = target
+	m.scrollToCursor()
+}
+
+// has reports whether an enabled item with value has arrived.
+func (m *streamModel) has(value string) bool {
+	for _, item := range m.items {
+		if value != "" && item.Value == value && !item.Disabled {
+			return true
+		}
+	}
+	return false
+}
+
+// chosenValue is the value confirmed with Enter: the default when the user did not move
+// the cursor and it has arrived, the item under the cursor otherwise.
+func (m *streamModel) chosenValue() string {
+	if !m.moved && m.has(m.defaultValue) {
+		return m.defaultValue
+	}
+	return m.items[m.cursor].Value
+}
+
+func (m *streamModel) firstEnabled() int {
+	for i, item := range m.items {
+		if !item.Disabled {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *streamModel) View() string {
+	var b strings.Builder
+	b.WriteString(common.FontColor(selector.DefaultHeader+"\n"+m.headerText, selector.ColorHeader) + "\n\n")
+
+	if len(m.items) == 0 {
+		b.WriteString(common.FontColor("  (no items yet)", selector.ColorUnSelected) + "\n")
+	}
+	end := m.offset + m.perPage
+	if end > len(m.items) {
+		end = len(m.items)
+	}
+	cursor := common.FontColor(selector.DefaultCursor, selector.ColorCursor)
+	for i := m.offset; i < end; i++ {
+		item := m.items[i]
+		if i == m.cursor {
+			line := fmt.Sprintf("[%d] %s", i+1, item.Label)
+			if item.Hint != "" {
+				line += fmt.Sprintf(" (%s)", item.Hint)
+			}
+			color := selector.ColorSelected
+			if item.Disabled {
+				line += " (disabled)"
+				color = "240"
+			}
+			b.WriteString(cursor + " " + common.FontColor(line, color) + "\n")
+			continue
+		}
+		line := fmt.Sprintf(" %d. %s", i+1, item.Label)
+		color := selector.ColorUnSelected
+		if item.Disabled {
+			line += " (disabled)"
+			color = "240"
+		}
+		b.WriteString("  " + common.FontColor(line, color) + "\n")
+	}
+
+	footer := m.footerText
+	if !m.complete {
+		loading := spinnerFrames[m.frame%len(spinnerFrames)] + " loading…"
+		if footer != "" {
+			footer += "  |  "
+		}
+		footer += loading
+	}
+	b.WriteString(common.FontColor(footer, selector.ColorFooter))
+	b.WriteString(m.timeout.view())
+	if m.showCancelMsg {
+		b.WriteString("\n" + common.FontColor("Press Ctrl+C again to exit", "yellow"))
+	}
+	return b.String()
+}
+
+// StartStreamSelection opens a single-choice list whose items can be appended, updated and
+// removed with the Stream* functions while it is open. It returns a handle for those
+// functions and for PollResult/WaitResult/CancelPrompt. The result carries the chosen value.
+func StartStreamSelection(jsonData, headerText, footerText string, perPage int, defaultValue, initialValue, backKey string, timeout int, resetTimeoutOnKey bool) int {
+	var items []ListItem
+	if jsonData != "" {
+		if err := json.Unmarshal([]byte(jsonData), &items); err != nil {
+			return startAsync(func(s *promptSession) string {
+				result, _ := json.Marshal(&StreamResult{
+					Value:      "",
+					resultMeta: s.meta(newPromptError(CodeInvalidInputJSON, "invalid items", err)),
+				})
+				return string(result)
+			})
+		}
+	}
+	src := newStreamSource(items)
+
+	streamSources.Lock()
+	defer streamSources.Unlock()
+	id := startAsync(func(s *promptSession) string {
+		return streamSelection(s, src, headerText, footerText, perPage, defaultValue, initialValue, backKey, timeout, resetTimeoutOnKey)
+	})
+	streamSources.sources[id] = src
+	return id
+}
+
+func streamSelection(s *promptSession, src *streamSource, headerText, footerText string, perPage int, defaultValue, initialValue, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	defer func() {
+		src.mu.Lock()
+		src.closed = true
+		src.mu.Unlock()
+		src.unregister()
+	}()
+
+	if perPage <= 0 {
+		perPage = 5
+	}
+	// Minimum height: header (2) + perPage items + footer (1) + buffer (2)
+	minTerminalHeight := perPage + 5
+
+	cursorValue := initialValue
+	if cursorValue == "" {
+		cursorValue = defaultValue
+	}
+	timeoutValue := defaultValue
+	if timeoutValue == "" {
+		timeoutValue = initialValue
+	}
+
+	m := &streamModel{
+		source:       src,
+		done:         make(chan struct{}),
+		perPage:      perPage,
+		headerText:   headerText,
+		footerText:   footerText,
+		initialValue: cursorValue,
+		defaultValue: defaultValue,
+		backKey:      backKey,
+		timeout:      newPromptTimeout(timeout, resetTimeoutOnKey),
+	}
+	m.timeout.hasDefault = timeoutValue != ""
+	m.refresh()
+
+	err := s.run(m, minTerminalHeight)
+	close(m.done)
+	if err != nil {
+		result, _ := json.Marshal(&StreamResult{
+			Value:      "",
+			resultMeta: s.meta(err),
+		})
+		return string(result)
+	}
+	if s.cancelled() {
+		result, _ := json.Marshal(&StreamResult{
+			Value:      "",
+			resultMeta: s.meta(ErrCancelled),
+		})
+		return string(result)
+	}
+	if m.timeout.expired {
+		// The default only counts if it actually arrived
+		if m.has(timeoutValue) {
+			result, _ := json.Marshal(&StreamResult{
+				Value:      timeoutValue,
+				resultMeta: s.meta(nil),
+			})
+			return string(result)
+		}
+		result, _ := json.Marshal(&StreamResult{
+			Value:      "",
+			resultMeta: s.meta(ErrTimeout),
+		})
+		return string(result)
+	}
+	if m.wentBack {
+		value := ""
+		if m.cursor < len(m.items) && !m.items[m.cursor].Disabled {
+			value = m.items[m.cursor].Value
+		}
+		result, _ := json.Marshal(&StreamResult{
+			Value:      value,
+			resultMeta: s.meta(ErrBack),
+		})
+		return string(result)
+	}
+	if m.canceled || !m.chosen {
+		result, _ := json.Marshal(&StreamResult{
+			Value:      "",
+			resultMeta: s.meta(ErrCancelled),
+		})
+		return string(result)
+	}
+	result, _ := json.Marshal(&StreamResult{
+		Value:      m.chosenValue(),
+		resultMeta: s.meta(nil),
+	})
+	return string(result)
+}
@@ -0,0 +1,77 @@
+package prompts
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestStreamSourceUnregisteredWhenClosed(t *testing.T) {
+	// An input that never ends keeps the prompt open until it is cancelled
+	input, closeInput := io.Pipe()
+	defer closeInput.Close()
+	SetTerminal(input, io.Discard)
+	defer SetTerminal(nil, nil)
+
+	id := StartStreamSelection(`[{"value":"a"}]`, "", "", 0, "", "", "", 0, false)
+	CancelPrompt(id)
+
+	var result StreamResult
+	if err := json.Unmarshal([]byte(WaitResult(id)), &result); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if result.Code != CodeCancelled {
+		t.Fatalf("result code = %q, want %q", result.Code, CodeCancelled)
+	}
+
+	streamSources.Lock()
+	_, registered := streamSources.sources[id]
+	streamSources.Unlock()
+	if registered {
+		t.Fatal("closed stream is still registered")
+	}
+	if StreamAppend(id, `[{"value":"b"}]`) {
+		t.Fatal("append to a closed stream succeeded")
+	}
+}
+
+func TestStreamChangeKeepsSingleTickChain(t *testing.T) {
+	m := &streamModel{source: newStreamSource(nil), done: make(chan struct{}), perPage: 5}
+	defer close(m.done)
+
+	m.source.items = []ListItem{{Value: "a"}}
+	m.source.changed()
+	_, cmd := m.Update(streamChangedMsg{})
+	if cmd == nil {
+		t.Fatal("change did not keep listening")
+	}
+	// The command only listens: it returns the next change instead of a tick
+	m.source.changed()
+	if msg := cmd(); msg != (streamChangedMsg{}) {
+		t.Fatalf("change returned %T, want only the listener", msg)
+	}
+	if len(m.items) != 1 {
+		t.Fatalf("items = %v after change", m.items)
+	}
+}
+
+func TestStreamEnterReturnsDefaultUntilMoved(t *testing.T) {
+	m := &streamModel{source: newStreamSource(nil), done: make(chan struct{}), perPage: 5, initialValue: "b", defaultValue: "c"}
+	defer close(m.done)
+
+	m.source.items = []ListItem{{Value: "a"}, {Value: "b"}, {Value: "c"}}
+	m.refresh()
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.chosen || m.chosenValue() != "c" {
+		t.Fatalf("Enter without moving chose %q, want the default %q", m.chosenValue(), "c")
+	}
+
+	m.chosen = false
+	m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.chosen || m.chosenValue() != "a" {
+		t.Fatalf("Enter after moving chose %q, want the item under the cursor %q", m.chosenValue(), "a")
+	}
+}
@@ -0,0 +1,414 @@
+package prompts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/mritd/bubbles/common"
+
+	"github.com/mritd/bubbles/selector"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type TagsResult struct {
+	Values []string `json:"values"`
+	resultMeta
+}
+
+type tagsModel struct {
+	suggestions []ListItem
+	tags        []string
+	input       textinput.Model
+	// cursor is the highlighted suggestion of filtered(), -1 for none
+	cursor int
+	// chip is the focused tag of the chip row, -1 while the text field has the focus
+	chip             int
+	perPage          int
+	headerText       string
+	footerText       string
+	err              string
+	submitted        bool
+	ctrlCPressedOnce bool
+	ctrlCPressTime   time.Time
+	showCancelMsg    bool
+	canceled         bool
+	backKey          string
+	wentBack         bool
+	timeout          promptTimeout
+	// preselected are the initial tags, returned on timeout
+	preselected []string
+}
+
+func (m *tagsModel) Init() tea.Cmd {
+	return m.timeout.init()
+}
+
+type tagsResetCancelMsg struct{}
+
+func (m *tagsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if handled, cmd := m.timeout.update(msg); handled {
+		return m, cmd
+	}
+
+	// Handle double Ctrl+C first
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "ctrl+c" {
+		now := time.Now()
+		if m.ctrlCPressedOnce && now.Sub(m.ctrlCPressTime) < 2*time.Second {
+			// Second Ctrl+C within 2 seconds - actually cancel
+			m.canceled = true
+			return m, tea.Quit
+		}
+		// First Ctrl+C - show message and set timer
+		m.ctrlCPressedOnce = true
+		m.ctrlCPressTime = now
+		m.showCancelMsg = true
+		// Reset after 2 seconds
+		return m, tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
+			return tagsResetCancelMsg{}
+		})
+	}
+
+	// Handle reset message
+	if _, ok := msg.(tagsResetCancelMsg); ok {
+		// Reset cancel state after timeout
+		m.ctrlCPressedOnce = false
+		m.showCancelMsg = false
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if isBackKey(keyMsg, m.backKey) {
+		m.wentBack = true
+		return m, tea.Quit
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyEnter:
+		switch {
+		case m.chip >= 0:
+			m.chip = -1
+		case m.cursor >= 0:
+			m.add(m.filtered()[m.cursor].Value)
+		case strings.TrimSpace(m.input.Value()) != "":
+			m.add(m.input.Value())
+		default:
+			// Enter on an empty text field confirms the tags
+			m.submitted = true
+			return m, tea.Quit
+		}
+		return m, nil
+	case tea.KeyUp:
+		m.moveCursor(-1)
+		return m, nil
+	case tea.KeyDown:
+		m.moveCursor(1)
+		return m, nil
+	case tea.KeyLeft:
+		// Left on an empty text field walks the chip row
+		if m.input.Value() == "" && len(m.tags) > 0 {
+			if m.chip < 0 {
+				m.chip = len(m.tags) - 1
+			} else if m.chip > 0 {
+				m.chip--
+			}
+			return m, nil
+		}
+	case tea.KeyRight:
+		if m.chip >= 0 {
+			m.chip++
+			if m.chip >= len(m.tags) {
+				m.chip = -1
+			}
+			return m, nil
+		}
+	case tea.KeyBackspace, tea.KeyDelete:
+		if m.chip >= 0 {
+			m.remove(m.chip)
+			return m, nil
+		}
+		// Backspace on an empty text field focuses the last tag, a second one removes it
+		if keyMsg.Type == tea.KeyBackspace && m.input.Value() == "" && len(m.tags) > 0 {
+			m.chip = len(m.tags) - 1
+			return m, nil
+		}
+	case tea.KeyEsc:
+		switch {
+		case m.chip >= 0:
+			m.chip = -1
+		case m.cursor >= 0:
+			m.cursor = -1
+		default:
+			m.input.Reset()
+			m.err = ""
+		}
+		return m, nil
+	case tea.KeySpace:
+		// Convert space key to rune message, textinput only handles runes
+		keyMsg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}}
+	}
+
+	// Everything else edits the text field
+	m.chip = -1
+	before := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(keyMsg)
+	if m.input.Value() != before {
+		m.cursor = -1
+		m.err = ""
+	}
+	return m, cmd
+}
+
+// filtered returns the suggestions that are not added yet and match the typed text.
+func (m *tagsModel) filtered() []ListItem {
+	query := strings.ToLower(strings.TrimSpace(m.input.Value()))
+	items := []ListItem{}
+	for _, item := range m.suggestions {
+		if m.indexOf(item.Value) >= 0 {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(item.Label), query) && !strings.Contains(strings.ToLower(item.Value), query) {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// moveCursor highlights the next enabled suggestion in direction; moving up past the
+// first one returns the focus to the text field.
+func (m *tagsModel) moveCursor(direction int) {
+	items := m.filtered()
+	m.chip = -1
+	for next := m.cursor + direction; next >= 0 && next < len(items); next += direction {
+		if !items[next].Disabled {
+			m.cursor = next
+			return
+		}
+	}
+	if direction < 0 {
+		m.cursor = -1
+	}
+}
+
+// indexOf returns the position of value among the tags, compared case-insensitively, or -1.
+func (m *tagsModel) indexOf(value string) int {
+	for i, tag := range m.tags {
+		if strings.EqualFold(tag, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+// add creates a tag from value. Typed text matching a suggestion adds the suggestion's
+// value, and duplicates are refused with a message.
+func (m *tagsModel) add(value string) {
+	value = strings.TrimSpace(value)
+	for _, item := range m.suggestions {
+		if strings.EqualFold(item.Value, value) || strings.EqualFold(item.Label, value) {
+			if item.Disabled {
+				m.err = fmt.Sprintf("%q is not available", value)
+				return
+			}
+			value = item.Value
+			break
+		}
+	}
+	if value == "" {
+		return
+	}
+	if m.indexOf(value) >= 0 {
+		m.err = fmt.Sprintf("%q is already added", value)
+		return
+	}
+	m.tags = append(m.tags, value)
+	m.input.Reset()
+	m.cursor = -1
+	m.err = ""
+}
+
+// remove drops the tag at i and keeps the chip focus on a neighbouring tag.
+func (m *tagsModel) remove(i int) {
+	m.tags = append(m.tags[:i], m.tags[i+1:]...)
+	if m.chip >= len(m.tags) {
+		m.chip = len(m.tags) - 1
+	}
+}
+
+func (m *tagsModel) View() string {
+	var b strings.Builder
+	if m.headerText != "" {
+		b.WriteString(common.FontColor(m.headerText, selector.ColorHeader) + "\n")
+	}
+
+	for i, tag := range m.tags {
+		color := selector.ColorUnSelected
+		if i == m.chip {
+			color = selector.ColorSelected
+		}
+		b.WriteString(common.FontColor("["+tag+"]", color) + " ")
+	}
+	b.WriteString(common.FontColor("›", selector.ColorHeader) + " " + m.input.View())
+	if m.err != "" {
+		b.WriteString("\n" + common.FontColor("✘ "+m.err, "1"))
+	}
+
+	items := m.filtered()
+	start := 0
+	if m.cursor >= m.perPage {
+		start = m.cursor - m.perPage + 1
+	}
+	for i := start; i < len(items) && i < start+m.perPage; i++ {
+		item := items[i]
+		line := item.Label
+		if line == "" {
+			line = item.Value
+		}
+		if item.Hint != "" {
+			line = fmt.Sprintf("%s (%s)", line, item.Hint)
+		}
+		switch {
+		case item.Disabled:
+			b.WriteString("\n" + common.FontColor("  "+line+" (disabled)", "240"))
+		case i == m.cursor:
+			b.WriteString("\n" + common.FontColor("» "+line, selector.ColorSelected))
+		default:
+			b.WriteString("\n" + common.FontColor("  "+line, selector.ColorUnSelected))
+		}
+	}
+
+	footer := m.footerText
+	if footer == "" {
+		footer = "Enter: add, ↑/↓: suggestions, Backspace: remove, Enter on an empty line: confirm"
+	}
+	b.WriteString("\n" + common.FontColor(footer, selector.ColorFooter))
+	b.WriteString(m.timeout.view())
+	if m.showCancelMsg {
+		b.WriteString("\n" + common.FontColor("Press Ctrl+C again to exit", "yellow"))
+	}
+	return b.String()
+}
+
+// Tags asks for a list of values: typed text becomes a new tag on Enter, jsonData holds
+// suggestions picked with the arrow keys, and Enter on an empty line returns the tags.
+// preselectedValues is a JSON array of initial tags, also returned on timeout.
+func Tags(jsonData, headerText, footerText string, perPage int, preselectedValues, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	return tags(newPromptSession(), jsonData, headerText, footerText, perPage, preselectedValues, backKey, timeout, resetTimeoutOnKey)
+}
+
+func tags(s *promptSession, jsonData, headerText, footerText string, perPage int, preselectedValues, backKey string, timeout int, resetTimeoutOnKey bool) string {
+	var items []ListItem
+	if jsonData != "" {
+		if err := parseJSONArg(jsonData, "suggestions", &items); err != nil {
+			result, _ := json.Marshal(&TagsResult{Values: []string{}, resultMeta: s.meta(err)})
+			return string(result)
+		}
+	}
+	var preselected []string
+	if preselectedValues != "" {
+		if err := parseJSONArg(preselectedValues, "preselected values", &preselected); err != nil {
+			result, _ := json.Marshal(&TagsResult{Values: []string{}, resultMeta: s.meta(err)})
+			return string(result)
+		}
+	}
+	o := TagsOptions{
+		PromptOptions: PromptOptions{
+			Header:            headerText,
+			Footer:            footerText,
+			BackKey:           backKey,
+			Timeout:           time.Duration(timeout) * time.Second,
+			ResetTimeoutOnKey: resetTimeoutOnKey,
+		},
+		PerPage:     perPage,
+		Preselected: preselected,
+	}
+	values, err := o.run(s, items)
+	if values == nil {
+		values = []string{}
+	}
+	result, _ := json.Marshal(&TagsResult{
+		Values:     values,
+		resultMeta: s.meta(err),
+	})
+	return string(result)
+}
+
+func (o TagsOptions) run(s *promptSession, items []ListItem) ([]string, error) {
+	if err := o.validate(items); err != nil {
+		return nil, err
+	}
+	perPage := o.PerPage
+	if perPage <= 0 {
+		perPage = 5
+	}
+
+	// Minimum height: header (1) + tags (1) + perPage suggestions + footer (1) + buffer (2)
+	minTerminalHeight := perPage + 5
+
+	m := o.newModel(items, perPage)
+	err := s.run(m, minTerminalHeight)
+	if err != nil {
+		return nil, err
+	}
+	if s.cancelled() {
+		return nil, ErrCancelled
+	}
+	return m.outcome()
+}
+
+// newModel builds the tags model with items as suggestions, showing perPage of them at a time.
+func (o TagsOptions) newModel(items []ListItem, perPage int) *tagsModel {
+	input := textinput.NewModel()
+	input.Prompt = ""
+	input.SetCursorMode(textinput.CursorStatic)
+	input.Focus()
+
+	preselected := make([]string, 0, len(o.Preselected))
+	for _, value := range o.Preselected {
+		preselected = append(preselected, strings.TrimSpace(value))
+	}
+
+	m := &tagsModel{
+		suggestions: items,
+		tags:        append([]string{}, preselected...),
+		input:       input,
+		cursor:      -1,
+		chip:        -1,
+		perPage:     perPage,
+		headerText:  o.Header,
+		footerText:  o.Footer,
+		backKey:     o.BackKey,
+		timeout:     newPromptTimeout(o.timeoutSeconds(), o.ResetTimeoutOnKey),
+		preselected: preselected,
+	}
+	// On timeout, resolve with the preselected tags
+	m.timeout.hasDefault = len(preselected) > 0
+	return m
+}
+
+// outcome is the answer of the tags prompt once its program has quit.
+func (m *tagsModel) outcome() ([]string, error) {
+	if m.timeout.expired {
+		if !m.timeout.hasDefault {
+			return nil, ErrTimeout
+		}
+		return m.preselected, nil
+	}
+	if m.wentBack {
+		// The current tags are returned as the partial value
+		return m.tags, ErrBack
+	}
+	if m.canceled || !m.submitted {
+		return nil, ErrCancelled
+	}
+	return m.tags, nil
+}
@@ -0,0 +1,147 @@
+package prompts
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mritd/bubbles/common"
+
+	"github.com/mritd/bubbles/selector"
+)
+
+var spinnerFrames = []string{"◒", "◐", "◓", "◑"}
+
+type taskState int
+
+const (
+	taskRunning taskState = iota
+	taskSucceeded
+	taskFailed
+)
+
+// spinnerTask is a single spinner line with an elapsed timer.
+type spinnerTask struct {
+	text    string
+	state   taskState
+	started time.Time
+	ended   time.Time
+	// frames, delay and color style the spinner; the shared frames, frame rate and color when empty
+	frames []string
+	delay  time.Duration
+	color  string
+	// hideElapsed leaves out the elapsed timer
+	hideElapsed bool
+}
+
+func (t *spinnerTask) view(frame int, now time.Time) string {
+	end := now
+	if t.done() {
+		end = t.ended
+	}
+	elapsed := ""
+	if !t.hideElapsed {
+		elapsed = " " + common.FontColor(formatElapsed(end.Sub(t.started)), selector.ColorFooter)
+	}
+	switch t.state {
+	case taskSucceeded:
+		return fmt.Sprintf("%s %s%s", common.FontColor("✔", successColor), t.text, elapsed)
+	case taskFailed:
+		return fmt.Sprintf("%s %s%s", common.FontColor("✘", failureColor), t.text, elapsed)
+	}
+	frames, color := spinnerFrames, selector.ColorSelected
+	if len(t.frames) > 0 {
+		frames = t.frames
+	}
+	if t.color != "" {
+		color = t.color
+	}
+	// The renderer redraws every liveFrameInterval, so a shorter delay skips frames
+	if t.delay > 0 {
+		frame = int(now.Sub(t.started) / t.delay)
+	}
+	return fmt.Sprintf("%s %s%s", common.FontColor(frames[frame%len(frames)], color), t.text, elapsed)
+}
+
+func (t *spinnerTask) done() bool {
+	return t.state != taskRunning
+}
+
+func (t *spinnerTask) interrupt(now time.Time) {
+	t.state = taskFailed
+	t.ended = now
+	t.text += " (cancelled)"
+}
+
+// formatElapsed renders a duration as "[12s]" or "[1m 5s]".
+func formatElapsed(d time.Duration) string {
+	seconds := int(d.Seconds())
+	if seconds < 60 {
+		return fmt.Sprintf("[%ds]", seconds)
+	}
+	return fmt.Sprintf("[%dm %ds]", seconds/60, seconds%60)
+}
+
+// SpinnerStart adds a running spinner line and returns its handle. Several spinners
+// can run at once and are rendered together with progress bars.
+func SpinnerStart(text string) int {
+	return SpinnerStartStyled(text, "", 0, "", false)
+}
+
+// SpinnerStartStyled is SpinnerStart with custom frames (a JSON array of strings), frame
+// delay in milliseconds and color (an ANSI color number or a hex color). Empty or zero
+// values keep the defaults, and hideElapsed leaves out the elapsed timer.
+func SpinnerStartStyled(text, framesJSON string, delayMs int, color string, hideElapsed bool) int {
+	var frames []string
+	if framesJSON != "" {
+		// Invalid frames keep the default ones, a spinner has no result to report them in
+		_ = json.Unmarshal([]byte(framesJSON), &frames)
+	}
+	return live.add(&spinnerTask{
+		text:        text,
+		state:       taskRunning,
+		started:     time.Now(),
+		frames:      frames,
+		delay:       time.Duration(delayMs) * time.Millisecond,
+		color:       color,
+		hideElapsed: hideElapsed,
+	})
+}
+
+// SpinnerUpdate replaces the text of a running spinner. It returns false for unknown handles.
+func SpinnerUpdate(id int, text string) bool {
+	return live.update(id, func(entry liveEntry) {
+		if task, ok := entry.(*spinnerTask); ok && !task.done() {
+			task.text = text
+		}
+	})
+}
+
+// SpinnerSucceed marks a spinner as succeeded, optionally replacing its text.
+func SpinnerSucceed(id int, text string) bool {
+	return spinnerEnd(id, taskSucceeded, text)
+}
+
+// SpinnerFail marks a spinner as failed, optionally replacing its text.
+func SpinnerFail(id int, text string) bool {
+	return spinnerEnd(id, taskFailed, text)
+}
+
+// SpinnerStop removes a spinner without leaving a final line.
+func SpinnerStop(id int) bool {
+	return live.remove(id)
+}
+
+func spinnerEnd(id int, state taskState, text string) bool {
+	return live.finish(id, func(entry liveEntry) {
+		task, ok := entry.(*spinnerTask)
+		if !ok || task.done() {
+			return
+		}
+		task.state = state
+		task.ended = time.Now()
+		if text != "" {
+			task.text = text
+		}
+	})
+}
@@ -1,13 +1,40 @@
 package prompts
 
 import (
+	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"golang.org/x/term"
 )
 
+// defaultTerminalPath is opened for rendering and input so that stdout stays free for data.
+const defaultTerminalPath = "/dev/tty"
+
+// stdioTerminal is the DLER_PROMPT_TTY / SetTerminalPath value that renders on stdin/stdout.
+const stdioTerminal = "stdio"
+
+// promptTerminal is where prompts read keys from and render to.
+var promptTerminal = struct {
+	sync.Mutex
+	input  io.Reader
+	output io.Writer
+	// custom is set when the caller chose the terminal with SetTerminal or SetTerminalPath
+	custom bool
+	// opened is the device opened for the default terminal, it stays open for the process lifetime
+	opened     *os.File
+	openFailed bool
+	// pathFile is the device opened by SetTerminalPath, closed once it is replaced and no
+	// program started on it is running anymore
+	pathFile *os.File
+	// users counts the running programs of each device opened by SetTerminalPath
+	users map[*os.File]int
+}{users: make(map[*os.File]int)}
+
 var ciEnvKeys = []string{
 	"CI",
 	"GITHUB_ACTIONS",
@@ -51,9 +78,137 @@ func hasUsableTermInfo() bool {
 	return true
 }
 
+// SetTerminal makes prompts read keys from input and render to output. Passing nil for
+// both restores the default: the controlling terminal (DLER_PROMPT_TTY, /dev/tty), falling
+// back to stdin/stdout when it cannot be opened.
+func SetTerminal(input io.Reader, output io.Writer) {
+	setTerminal(input, output, nil)
+}
+
+// setTerminal replaces the prompt terminal like SetTerminal. The device a previous
+// SetTerminalPath opened is closed, or once the prompt or live program still running on it
+// quits. pathFile is the device opened for the new terminal, if any.
+func setTerminal(input io.Reader, output io.Writer, pathFile *os.File) {
+	promptTerminal.Lock()
+	defer promptTerminal.Unlock()
+	if previous := promptTerminal.pathFile; previous != nil && promptTerminal.users[previous] == 0 {
+		previous.Close()
+	}
+	promptTerminal.pathFile = pathFile
+	if input == nil && output == nil {
+		promptTerminal.input, promptTerminal.output, promptTerminal.custom = nil, nil, false
+		return
+	}
+	if input == nil {
+		input = os.Stdin
+	}
+	if output == nil {
+		output = os.Stdout
+	}
+	promptTerminal.input, promptTerminal.output, promptTerminal.custom = input, output, true
+}
+
+// SetTerminalPath makes prompts use the terminal device at path, "stdio" for stdin/stdout,
+// or the default when path is empty. The device opened by a previous call is closed once
+// no prompt uses it anymore.
+func SetTerminalPath(path string) error {
+	switch path {
+	case "":
+		SetTerminal(nil, nil)
+	case stdioTerminal:
+		SetTerminal(os.Stdin, os.Stdout)
+	default:
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			return fmt.Errorf("failed to open terminal: %w", err)
+		}
+		setTerminal(f, f, f)
+	}
+	return nil
+}
+
+// terminalIO returns the reader and writer prompts use for input and rendering.
+func terminalIO() (io.Reader, io.Writer) {
+	promptTerminal.Lock()
+	defer promptTerminal.Unlock()
+	return terminalIOLocked()
+}
+
+func terminalIOLocked() (io.Reader, io.Writer) {
+	if promptTerminal.custom {
+		return promptTerminal.input, promptTerminal.output
+	}
+
+	path := strings.TrimSpace(os.Getenv("DLER_PROMPT_TTY"))
+	if path == stdioTerminal {
+		return os.Stdin, os.Stdout
+	}
+	if path == "" {
+		path = defaultTerminalPath
+	}
+	if promptTerminal.opened == nil && !promptTerminal.openFailed {
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			promptTerminal.openFailed = true
+		} else {
+			promptTerminal.opened = f
+		}
+	}
+	if promptTerminal.opened == nil {
+		return os.Stdin, os.Stdout
+	}
+	return promptTerminal.opened, promptTerminal.opened
+}
+
+// terminalOutputFile returns the rendering output when it is a file (stdout otherwise),
+// so that size queries and resize checks look at the terminal prompts are drawn on.
+func terminalOutputFile() *os.File {
+	_, output := terminalIO()
+	if f, ok := output.(*os.File); ok {
+		return f
+	}
+	return os.Stdout
+}
+
+func terminalFd() int {
+	return int(terminalOutputFile().Fd())
+}
+
+// terminalProgramOptions wires a program to the prompt terminal.
+// terminalProgramOptions are the options of a program on the prompt terminal. The program
+// must call done once it quits so that a device replaced in the meantime can be closed.
+func terminalProgramOptions() (opts []tea.ProgramOption, done func()) {
+	promptTerminal.Lock()
+	input, output := terminalIOLocked()
+	device := promptTerminal.pathFile
+	if device != nil {
+		promptTerminal.users[device]++
+	}
+	promptTerminal.Unlock()
+
+	done = func() {
+		if device == nil {
+			return
+		}
+		promptTerminal.Lock()
+		defer promptTerminal.Unlock()
+		promptTerminal.users[device]--
+		if promptTerminal.users[device] == 0 {
+			delete(promptTerminal.users, device)
+			if device != promptTerminal.pathFile {
+				device.Close()
+			}
+		}
+	}
+	return []tea.ProgramOption{tea.WithInput(input), tea.WithOutput(output)}, done
+}
+
 func isFullyInteractiveTTY() bool {
-	return terminalDescriptorIsTTY(os.Stdin) &&
-		terminalDescriptorIsTTY(os.Stdout) &&
+	input, output := terminalIO()
+	inputFile, _ := input.(*os.File)
+	outputFile, _ := output.(*os.File)
+	return terminalDescriptorIsTTY(inputFile) &&
+		terminalDescriptorIsTTY(outputFile) &&
 		hasUsableTermInfo()
 }
 
@@ -68,10 +223,19 @@ func shouldValidateTerminalSize() bool {
 }
 
 func getTerminalHeight() (int, error) {
-	fd := int(os.Stdout.Fd())
+	fd := terminalFd()
 	_, height, err := term.GetSize(fd)
 	if err != nil {
 		return 0, err
 	}
 	return height, nil
 }
+
+func getTerminalWidth() (int, error) {
+	fd := terminalFd()
+	width, _, err := term.GetSize(fd)
+	if err != nil {
+		return 0, err
+	}
+	return width, nil
+}
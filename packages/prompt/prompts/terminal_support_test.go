@@ -0,0 +1,33 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetTerminalPathKeepsDeviceOpenWhileInUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tty")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetTerminalPath(path); err != nil {
+		t.Fatalf("SetTerminalPath = %v", err)
+	}
+	defer SetTerminal(nil, nil)
+	promptTerminal.Lock()
+	device := promptTerminal.pathFile
+	promptTerminal.Unlock()
+
+	// A program is running on the device when the terminal is switched
+	_, done := terminalProgramOptions()
+	SetTerminal(nil, nil)
+	if _, err := device.Stat(); err != nil {
+		t.Fatalf("device closed while a program uses it: %v", err)
+	}
+
+	done()
+	if _, err := device.Stat(); err == nil {
+		t.Fatal("replaced device still open after its program quit")
+	}
+}
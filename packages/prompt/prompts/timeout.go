@@ -0,0 +1,96 @@
+package prompts
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mritd/bubbles/common"
+
+	"github.com/mritd/bubbles/selector"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// timeoutError is the error returned when a prompt times out and has no default to fall back to.
+const timeoutError = "Timeout"
+
+type timeoutTickMsg struct{}
+
+// promptTimeout is the optional countdown shared by all prompts. When it expires the
+// prompt quits and resolves with its default/initial value, or with timeoutError.
+type promptTimeout struct {
+	duration   time.Duration
+	resetOnKey bool
+	deadline   time.Time
+	expired    bool
+	// hasDefault reports whether the prompt resolves with a value on expiry (only affects the footer text)
+	hasDefault bool
+}
+
+// newPromptTimeout creates a countdown of the given number of seconds; 0 or less disables it.
+func newPromptTimeout(seconds int, resetOnKey bool) promptTimeout {
+	if seconds <= 0 {
+		return promptTimeout{}
+	}
+	duration := time.Duration(seconds) * time.Second
+	return promptTimeout{
+		duration:   duration,
+		resetOnKey: resetOnKey,
+		deadline:   time.Now().Add(duration),
+	}
+}
+
+func (t *promptTimeout) enabled() bool {
+	return t.duration > 0
+}
+
+// init returns the command that starts the countdown ticks.
+func (t *promptTimeout) init() tea.Cmd {
+	if !t.enabled() {
+		return nil
+	}
+	return t.tick()
+}
+
+func (t *promptTimeout) tick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return timeoutTickMsg{}
+	})
+}
+
+// update advances the countdown. It reports whether msg was consumed, in which case
+// the returned command must be used as-is (tea.Quit once the countdown expired).
+func (t *promptTimeout) update(msg tea.Msg) (bool, tea.Cmd) {
+	if !t.enabled() {
+		return false, nil
+	}
+	switch msg.(type) {
+	case timeoutTickMsg:
+		if !time.Now().Before(t.deadline) {
+			t.expired = true
+			return true, tea.Quit
+		}
+		return true, t.tick()
+	case tea.KeyMsg:
+		if t.resetOnKey {
+			t.deadline = time.Now().Add(t.duration)
+		}
+	}
+	return false, nil
+}
+
+// view renders the countdown footer line, or "" when the countdown is disabled.
+func (t *promptTimeout) view() string {
+	if !t.enabled() || t.expired {
+		return ""
+	}
+	remaining := time.Until(t.deadline).Round(time.Second)
+	if remaining < 0 {
+		remaining = 0
+	}
+	text := fmt.Sprintf("Timing out in %ds", int(remaining.Seconds()))
+	if t.hasDefault {
+		text = fmt.Sprintf("Continuing with the default in %ds", int(remaining.Seconds()))
+	}
+	return "\n" + common.FontColor(text, selector.ColorFooter)
+}
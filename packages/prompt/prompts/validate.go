@@ -0,0 +1,234 @@
+package prompts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// invalidItems is reported when the items of a prompt are well-formed JSON but cannot be used.
+func invalidItems(format string, args ...interface{}) error {
+	return &PromptError{Code: CodeInvalidInput, Message: "invalid items", Details: fmt.Sprintf(format, args...)}
+}
+
+// parseJSONArg decodes a JSON argument of the string based API. name describes the
+// argument in the error, e.g. "items".
+func parseJSONArg(data, name string, v interface{}) error {
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		return newPromptError(CodeInvalidInputJSON, "invalid "+name, err)
+	}
+	return nil
+}
+
+// validateListItems checks the items of a selection before anything is rendered: the list
+// must not be empty, values must be unique and at least one item must be enabled.
+// Separators and section labels have no value and cannot be selected or open submenus.
+func validateListItems(items []ListItem) error {
+	if len(items) == 0 {
+		return invalidItems("the list is empty")
+	}
+	seen := make(map[string]bool, len(items))
+	enabled := false
+	for i, item := range items {
+		switch item.Kind {
+		case "":
+		case ItemSeparator, ItemLabel:
+			if len(item.Children) > 0 {
+				return invalidItems("%s at index %d cannot have children", item.Kind, i)
+			}
+			continue
+		default:
+			return invalidItems("unknown kind %q at index %d", item.Kind, i)
+		}
+		if seen[item.Value] {
+			return invalidItems("duplicate value %q at index %d", item.Value, i)
+		}
+		seen[item.Value] = true
+		if !item.Disabled {
+			enabled = true
+		}
+	}
+	if !enabled {
+		return invalidItems("all items are disabled")
+	}
+	return nil
+}
+
+// validateInitialValue checks that the value a cursor starts on, if any, is one of the
+// items and can be focused.
+func validateInitialValue(initial string, lookup func(value string) (isDisabled, found bool)) error {
+	if initial == "" {
+		return nil
+	}
+	isDisabled, found := lookup(initial)
+	if !found {
+		return invalidItems("initial value %q is not one of the items", initial)
+	}
+	if isDisabled {
+		return invalidItems("initial value %q is disabled", initial)
+	}
+	return nil
+}
+
+// validatePreselected checks that every preselected value is one of the items.
+func validatePreselected(preselected []string, known func(value string) bool) error {
+	for _, value := range preselected {
+		if !known(value) {
+			return invalidItems("preselected value %q is not one of the items", value)
+		}
+	}
+	return nil
+}
+
+// listItemLookup finds the option with a value; sections are never found.
+func listItemLookup(items []ListItem) func(value string) (bool, bool) {
+	return func(value string) (bool, bool) {
+		for _, item := range items {
+			if !item.isSection() && item.Value == value {
+				return item.Disabled, true
+			}
+		}
+		return false, false
+	}
+}
+
+// validateSubmenus applies the checks of validateListItems to the children of every
+// item that has some, at any depth.
+func validateSubmenus(items []ListItem) error {
+	for _, item := range items {
+		if len(item.Children) == 0 {
+			continue
+		}
+		if err := validateListItems(item.Children); err != nil {
+			var promptErr *PromptError
+			if errors.As(err, &promptErr) {
+				return invalidItems("submenu %q: %s", item.Value, promptErr.Details)
+			}
+			return err
+		}
+		if err := validateSubmenus(item.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o SelectOptions) validate(items []ListItem) error {
+	if err := validateListItems(items); err != nil {
+		return err
+	}
+	if err := validateSubmenus(items); err != nil {
+		return err
+	}
+	return validateInitialValue(o.Initial, listItemLookup(items))
+}
+
+func (o MultiselectOptions) validate(items []ListItem) error {
+	if err := validateListItems(items); err != nil {
+		return err
+	}
+	lookup := listItemLookup(items)
+	if err := validatePreselected(o.Preselected, func(value string) bool {
+		_, found := lookup(value)
+		return found
+	}); err != nil {
+		return err
+	}
+	return validateInitialValue(o.Initial, lookup)
+}
+
+// validateGroupItems checks grouped items: besides the checks of validateListItems every
+// item must belong to a group that has a header.
+func validateGroupItems(items []GroupListItem) error {
+	if len(items) == 0 {
+		return invalidItems("the list is empty")
+	}
+	groups := make(map[string]bool)
+	for _, item := range items {
+		if item.IsGroupHeader {
+			groups[item.GroupName] = true
+		}
+	}
+	seen := make(map[string]bool, len(items))
+	enabled := false
+	for i, item := range items {
+		if item.IsGroupHeader {
+			continue
+		}
+		if item.GroupName != "" && !groups[item.GroupName] {
+			return invalidItems("item %q at index %d references unknown group %q", item.Value, i, item.GroupName)
+		}
+		if seen[item.Value] {
+			return invalidItems("duplicate value %q at index %d", item.Value, i)
+		}
+		seen[item.Value] = true
+		if !item.Disabled {
+			enabled = true
+		}
+	}
+	if !enabled {
+		return invalidItems("all items are disabled")
+	}
+	return nil
+}
+
+// groupItemLookup finds the item with a value; group headers are never found.
+func groupItemLookup(items []GroupListItem) func(value string) (bool, bool) {
+	return func(value string) (bool, bool) {
+		for _, item := range items {
+			if !item.IsGroupHeader && item.Value == value {
+				return item.Disabled, true
+			}
+		}
+		return false, false
+	}
+}
+
+func (o GroupMultiselectOptions) validate(items []GroupListItem) error {
+	if err := validateGroupItems(items); err != nil {
+		return err
+	}
+	lookup := groupItemLookup(items)
+	if err := validatePreselected(o.Preselected, func(value string) bool {
+		_, found := lookup(value)
+		return found
+	}); err != nil {
+		return err
+	}
+	return validateInitialValue(o.Initial, lookup)
+}
+
+func (o GroupSelectOptions) validate(items []GroupListItem) error {
+	if err := validateGroupItems(items); err != nil {
+		return err
+	}
+	return validateInitialValue(o.Initial, groupItemLookup(items))
+}
+
+// validate checks the suggestions and initial tags of a tags prompt. Unlike a selection
+// the suggestions may be empty, since any value can be typed.
+func (o TagsOptions) validate(suggestions []ListItem) error {
+	seen := make(map[string]bool, len(suggestions))
+	for i, item := range suggestions {
+		if strings.TrimSpace(item.Value) == "" {
+			return invalidItems("empty value at index %d", i)
+		}
+		if seen[item.Value] {
+			return invalidItems("duplicate value %q at index %d", item.Value, i)
+		}
+		seen[item.Value] = true
+	}
+	tags := make(map[string]bool, len(o.Preselected))
+	for _, value := range o.Preselected {
+		key := strings.ToLower(strings.TrimSpace(value))
+		if key == "" {
+			return invalidItems("preselected values must not be empty")
+		}
+		if tags[key] {
+			return invalidItems("duplicate preselected value %q", value)
+		}
+		tags[key] = true
+	}
+	return nil
+}
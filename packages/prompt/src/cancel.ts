@@ -1,19 +1,92 @@
+import { ptr } from "bun:ffi";
+import { symbols } from "./ffi";
+import { encode } from "./utils";
+
 /**
  * Custom error class for prompt cancellations
  */
 export class PromptCancelledError extends Error {
-  constructor(message = "Cancelled") {
+  readonly reason?: string;
+
+  constructor(message = "Cancelled", reason?: string) {
     super(message);
     this.name = "PromptCancelledError";
+    this.reason = reason || undefined;
+  }
+}
+
+/**
+ * Error thrown when the user presses the configured `backKey` of a prompt.
+ * Carries the partial value the prompt held at that moment so the caller
+ * can re-open the previous step and later restore this one prefilled.
+ */
+export class PromptBackError<T = unknown> extends Error {
+  readonly value: T;
+
+  constructor(value: T, message = "Back") {
+    super(message);
+    this.name = "PromptBackError";
+    this.value = value;
+  }
+}
+
+/**
+ * Machine-readable error codes reported by the native prompts. Codes are a stable
+ * contract: they are never renamed or reused, new codes may be added.
+ */
+export type PromptErrorCode =
+  | "CANCELLED"
+  | "BACK"
+  | "TIMEOUT"
+  | "BUSY"
+  | "INVALID_INPUT_JSON"
+  | "INVALID_INPUT"
+  | "NO_TTY"
+  | "TERMINAL_TOO_SMALL"
+  | "RENDER_FAILED"
+  | "INVALID_HANDLE";
+
+/**
+ * Error thrown when a prompt fails for any reason other than cancellation or back navigation.
+ */
+export class PromptFailedError extends Error {
+  readonly code: PromptErrorCode;
+  readonly details?: string;
+
+  constructor(code: PromptErrorCode, message: string, details?: string) {
+    super(message);
+    this.name = "PromptFailedError";
+    this.code = code;
+    this.details = details || undefined;
   }
 }
 
+/**
+ * Checks if an error is a "go back" navigation request from a prompt
+ * @param error - The error to check
+ * @returns `true` if the user asked to return to the previous prompt
+ */
+export function isBack(error: unknown): error is PromptBackError {
+  return error instanceof PromptBackError;
+}
+
 /**
  * Throws a PromptCancelledError to signal that user cancelled the prompt
  * @param message - Optional custom cancellation message
+ * @param reason - Why the prompt was closed, when it was cancelled programmatically
+ */
+export function cancel(message = "Cancelled", reason?: string): never {
+  throw new PromptCancelledError(message, reason);
+}
+
+/**
+ * Closes every prompt that is currently open (e.g. when a background job fails).
+ * The pending prompt promises settle as cancelled with the given reason.
+ * @param reason - Reported as `reason` on the resulting PromptCancelledError
+ * @returns `true` if a prompt was open
  */
-export function cancel(message = "Cancelled"): never {
-  throw new PromptCancelledError(message);
+export function cancelActivePrompt(reason = ""): boolean {
+  return symbols.CancelActivePrompt(ptr(encode(reason)));
 }
 
 /**
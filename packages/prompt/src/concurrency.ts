@@ -0,0 +1,22 @@
+import { ptr } from "bun:ffi";
+import { symbols } from "./ffi";
+import { encode } from "./utils";
+
+/**
+ * What happens when a prompt is opened while another one is still on screen:
+ * - `queue`: wait until the open prompt is answered (default)
+ * - `reject`: fail the new prompt with a `busy` error
+ * - `preempt`: cancel the open prompt and show the new one
+ */
+export type PromptConcurrencyPolicy = "queue" | "reject" | "preempt";
+
+/**
+ * Sets the process-wide policy for concurrent prompts
+ * (also configurable with the `DLER_PROMPT_CONCURRENCY` environment variable)
+ * @param policy - The policy to apply to prompts opened from now on
+ */
+export function setPromptConcurrency(policy: PromptConcurrencyPolicy): void {
+  if (!symbols.SetConcurrencyPolicy(ptr(encode(policy)))) {
+    throw new Error(`Unknown prompt concurrency policy: ${policy}`);
+  }
+}
@@ -49,9 +49,29 @@ try {
         FFIType.bool,
         FFIType.ptr,
         FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+        FFIType.ptr,
       ],
       returns: FFIType.ptr,
     },
+    StartSelection: {
+      args: [
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+        FFIType.ptr,
+      ],
+      returns: FFIType.int,
+    },
     CreatePrompt: {
       args: [
         FFIType.ptr,
@@ -62,9 +82,28 @@ try {
         FFIType.ptr,
         FFIType.bool,
         FFIType.int,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
       ],
       returns: FFIType.ptr,
     },
+    StartPrompt: {
+      args: [
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.bool,
+        FFIType.int,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+      ],
+      returns: FFIType.int,
+    },
     CreateMultiselect: {
       args: [
         FFIType.ptr,
@@ -74,13 +113,55 @@ try {
         FFIType.bool,
         FFIType.ptr,
         FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+        FFIType.ptr,
       ],
       returns: FFIType.ptr,
     },
+    StartMultiselect: {
+      args: [
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+        FFIType.ptr,
+      ],
+      returns: FFIType.int,
+    },
     CreateConfirm: {
-      args: [FFIType.ptr, FFIType.ptr, FFIType.ptr, FFIType.ptr, FFIType.ptr],
+      args: [
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+      ],
       returns: FFIType.ptr,
     },
+    StartConfirm: {
+      args: [
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+      ],
+      returns: FFIType.int,
+    },
     CreateGroupMultiselect: {
       args: [
         FFIType.ptr,
@@ -92,7 +173,190 @@ try {
         FFIType.ptr,
         FFIType.ptr,
         FFIType.int,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+      ],
+      returns: FFIType.ptr,
+    },
+    StartGroupMultiselect: {
+      args: [
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+        FFIType.bool,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+      ],
+      returns: FFIType.int,
+    },
+    StartForm: {
+      args: [
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+      ],
+      returns: FFIType.int,
+    },
+    StartDangerConfirm: {
+      args: [
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+      ],
+      returns: FFIType.int,
+    },
+    StartExpand: {
+      args: [
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
       ],
+      returns: FFIType.int,
+    },
+    StartTags: {
+      args: [
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+      ],
+      returns: FFIType.int,
+    },
+    StartStreamSelection: {
+      args: [
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+      ],
+      returns: FFIType.int,
+    },
+    StreamAppend: {
+      args: [FFIType.int, FFIType.ptr],
+      returns: FFIType.bool,
+    },
+    StreamUpdate: {
+      args: [FFIType.int, FFIType.ptr],
+      returns: FFIType.bool,
+    },
+    StreamRemove: {
+      args: [FFIType.int, FFIType.ptr],
+      returns: FFIType.bool,
+    },
+    StreamComplete: {
+      args: [FFIType.int],
+      returns: FFIType.bool,
+    },
+    StartGroupSelect: {
+      args: [
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+        FFIType.ptr,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.ptr,
+        FFIType.int,
+        FFIType.bool,
+      ],
+      returns: FFIType.int,
+    },
+    SpinnerStart: {
+      args: [FFIType.ptr],
+      returns: FFIType.int,
+    },
+    SpinnerStartStyled: {
+      args: [FFIType.ptr, FFIType.ptr, FFIType.int, FFIType.ptr, FFIType.bool],
+      returns: FFIType.int,
+    },
+    SpinnerUpdate: {
+      args: [FFIType.int, FFIType.ptr],
+      returns: FFIType.bool,
+    },
+    SpinnerSucceed: {
+      args: [FFIType.int, FFIType.ptr],
+      returns: FFIType.bool,
+    },
+    SpinnerFail: {
+      args: [FFIType.int, FFIType.ptr],
+      returns: FFIType.bool,
+    },
+    SpinnerStop: {
+      args: [FFIType.int],
+      returns: FFIType.bool,
+    },
+    ProgressCreate: {
+      args: [FFIType.ptr, FFIType.i64],
+      returns: FFIType.int,
+    },
+    ProgressSetTotal: {
+      args: [FFIType.int, FFIType.i64],
+      returns: FFIType.bool,
+    },
+    ProgressIncrement: {
+      args: [FFIType.int, FFIType.i64],
+      returns: FFIType.bool,
+    },
+    ProgressSetMessage: {
+      args: [FFIType.int, FFIType.ptr],
+      returns: FFIType.bool,
+    },
+    ProgressFinish: {
+      args: [FFIType.int, FFIType.bool, FFIType.ptr],
+      returns: FFIType.bool,
+    },
+    PollResult: {
+      args: [FFIType.int],
+      returns: FFIType.ptr,
+    },
+    // Blocks the calling thread until the prompt is answered, awaitResult polls instead
+    WaitResult: {
+      args: [FFIType.int],
+      returns: FFIType.ptr,
+    },
+    CancelPrompt: {
+      args: [FFIType.int],
+      returns: FFIType.bool,
+    },
+    CancelActivePrompt: {
+      args: [FFIType.ptr],
+      returns: FFIType.bool,
+    },
+    SetConcurrencyPolicy: {
+      args: [FFIType.ptr],
+      returns: FFIType.bool,
+    },
+    SetTerminalPath: {
+      args: [FFIType.ptr],
       returns: FFIType.ptr,
     },
     FreeString: {
@@ -1,4 +1,4 @@
-import { PromptCancelledError } from "./cancel";
+import { PromptBackError, PromptCancelledError } from "./cancel";
 
 type MaybePromise<T> = T | Promise<T>;
 
@@ -8,6 +8,11 @@ export interface GroupPromptContext<
 > {
   key: TKey;
   results: Readonly<Partial<TResult>>;
+  /**
+   * Partial value captured when the user last navigated back from this prompt,
+   * or its previous answer when returning to it. Use it to prefill the prompt.
+   */
+  draft?: TResult[TKey];
 }
 
 export interface GroupPromptHandler<
@@ -30,18 +35,35 @@ export async function groupPrompt<TResult extends Record<string, unknown>>(
   options?: GroupPromptOptions,
 ): Promise<TResult> {
   const results: Partial<TResult> = {};
+  const drafts: Partial<TResult> = {};
   const keys = Object.keys(handlers) as (keyof TResult)[];
 
-  for (const key of keys) {
+  let index = 0;
+  while (index < keys.length) {
+    const key = keys[index] as keyof TResult;
     const handler = handlers[key] as GroupPromptHandler<TResult, typeof key>;
 
     try {
       const value = await handler({
         key,
         results: results as Readonly<Partial<TResult>>,
+        draft: drafts[key],
       });
       results[key] = value;
+      delete drafts[key];
+      index++;
     } catch (error) {
+      if (error instanceof PromptBackError) {
+        // Keep what was typed so far and re-open the previous prompt prefilled
+        drafts[key] = error.value as TResult[typeof key];
+        if (index > 0) {
+          index--;
+          const previousKey = keys[index] as keyof TResult;
+          drafts[previousKey] = results[previousKey];
+          delete results[previousKey];
+        }
+        continue;
+      }
       if (error instanceof PromptCancelledError && options?.onCancel) {
         await options.onCancel(error);
       }
@@ -1,5 +1,9 @@
 export * from "./cancel";
+export * from "./concurrency";
 export * from "./group";
+export * from "./progress";
 export * from "./prompt";
 export * from "./selection";
 export * from "./spinner";
+export * from "./stream";
+export * from "./terminal";
@@ -0,0 +1,63 @@
+import { ptr } from "bun:ffi";
+import { symbols } from "./ffi";
+import { encode } from "./utils";
+
+export interface ProgressOptions {
+  message: string;
+  total?: number; // expected amount in bytes; omit while the size is not known yet
+  silent?: boolean;
+}
+
+export interface ProgressInstance {
+  setTotal(total: number): ProgressInstance;
+  increment(delta: number): ProgressInstance;
+  setMessage(message: string): ProgressInstance;
+  succeed(message?: string): ProgressInstance;
+  fail(message?: string): ProgressInstance;
+}
+
+/**
+ * Creates a progress bar drawn by the native live renderer. Bars stack with other bars
+ * and spinners, show the throughput and ETA, and pause while a prompt is open.
+ */
+export function createProgress(options: ProgressOptions): ProgressInstance {
+  const { message, total = 0, silent = false } = options;
+
+  // The handle of the native bar until it is finished
+  let handle: number | null = silent
+    ? null
+    : symbols.ProgressCreate(ptr(encode(message)), Math.max(0, Math.trunc(total)));
+
+  const finish = (success: boolean, text?: string): ProgressInstance => {
+    if (handle !== null) {
+      symbols.ProgressFinish(handle, success, ptr(encode(text ?? "")));
+      handle = null;
+    }
+    return progress;
+  };
+
+  const progress: ProgressInstance = {
+    setTotal(newTotal: number) {
+      if (handle !== null) {
+        symbols.ProgressSetTotal(handle, Math.max(0, Math.trunc(newTotal)));
+      }
+      return progress;
+    },
+    increment(delta: number) {
+      if (handle !== null) {
+        symbols.ProgressIncrement(handle, Math.trunc(delta));
+      }
+      return progress;
+    },
+    setMessage(newMessage: string) {
+      if (handle !== null) {
+        symbols.ProgressSetMessage(handle, ptr(encode(newMessage)));
+      }
+      return progress;
+    },
+    succeed: (text?: string) => finish(true, text),
+    fail: (text?: string) => finish(false, text),
+  };
+
+  return progress;
+}
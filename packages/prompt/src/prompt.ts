@@ -1,7 +1,12 @@
 import { ptr } from "bun:ffi";
-import { cancel } from "./cancel";
+import {
+  cancel,
+  PromptBackError,
+  type PromptErrorCode,
+  PromptFailedError,
+} from "./cancel";
 import { symbols } from "./ffi";
-import { encode, toString } from "./utils";
+import { awaitResult, encode } from "./utils";
 
 function formatPromptText(title?: string, message?: string): string {
   if (title && message) {
@@ -22,6 +27,9 @@ export type InputPromptOptions = {
   validateErrPrefix?: string;
   defaultValue?: string;
   initialValue?: string;
+  backKey?: string; // e.g. "shift+tab" or "ctrl+b"; ends the prompt with a PromptBackError
+  timeout?: number; // seconds; resolves with the default value (or throws "Timeout") when it expires
+  resetTimeoutOnKey?: boolean;
   validate?: (value: string) => boolean | string | null | undefined;
 };
 
@@ -40,29 +48,40 @@ export async function inputPrompt(
   let currentInitialValue = initialValue;
 
   while (true) {
-    const returnedPtr = symbols.CreatePrompt(
-      ptr(encode(promptText)),
-      ptr(encode(options.echoMode || "normal")),
-      ptr(encode(options.validateOkPrefix || "")),
-      ptr(encode(options.validateErrPrefix || "")),
-      ptr(encode(currentDefaultValue || "")),
-      ptr(encode(currentInitialValue || "")),
-      options.required ?? true,
-      options.charLimit || 0,
+    const returned = await awaitResult(
+      symbols.StartPrompt(
+        ptr(encode(promptText)),
+        ptr(encode(options.echoMode || "normal")),
+        ptr(encode(options.validateOkPrefix || "")),
+        ptr(encode(options.validateErrPrefix || "")),
+        ptr(encode(currentDefaultValue || "")),
+        ptr(encode(currentInitialValue || "")),
+        options.required ?? true,
+        options.charLimit || 0,
+        ptr(encode(options.backKey || "")),
+        options.timeout ?? 0,
+        options.resetTimeoutOnKey ?? false,
+      ),
     );
-    const { value, error } = JSON.parse(toString(returnedPtr)) as {
+    const { value, error, code, details, reason } = JSON.parse(returned) as {
       value: string;
       error: string;
+      code?: PromptErrorCode;
+      details?: string;
+      reason?: string;
     };
+    if (code === "BACK") {
+      throw new PromptBackError(value);
+    }
     if (error !== "") {
-      if (error === "Cancelled") {
+      if (code === "CANCELLED") {
         if (options.required ?? true) {
-          cancel(error);
+          cancel(error, reason);
         }
         // If not required, return empty string when cancelled
         return "";
       }
-      throw new Error(error);
+      throw new PromptFailedError(code ?? "RENDER_FAILED", error, details);
     }
 
     // If no validation function, return immediately
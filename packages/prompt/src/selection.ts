@@ -1,7 +1,20 @@
 import { ptr } from "bun:ffi";
-import { cancel } from "./cancel";
+import {
+  cancel,
+  PromptBackError,
+  type PromptErrorCode,
+  PromptFailedError,
+} from "./cancel";
 import { symbols } from "./ffi";
-import { encode, toString } from "./utils";
+import { awaitResult, encode } from "./utils";
+
+// otherLabel converts the `other` option to the label of the "Other…" entry, "" for none.
+function otherLabel(other?: boolean | string): string {
+  if (other === true) {
+    return "Other…";
+  }
+  return other || "";
+}
 
 function formatPromptText(title?: string, message?: string): string {
   if (title && message) {
@@ -17,8 +30,27 @@ export type SelectionItem<T extends string = string> = {
   label: string;
   hint?: string;
   disabled?: boolean;
+  kind?: "separator" | "label"; // structures the list; skipped, not numbered and never returned
+  children?: readonly SelectionItem<T>[]; // opens a submenu in selectPrompt
 };
 
+function serializeSelectionItems(
+  items: readonly SelectionItem[],
+): Record<string, unknown>[] {
+  return items.map((item) => {
+    return {
+      value: item.value,
+      label: item.label,
+      hint: item.hint ?? "",
+      disabled: item.disabled ?? false,
+      ...(item.kind ? { kind: item.kind } : {}),
+      ...(item.children?.length
+        ? { children: serializeSelectionItems(item.children) }
+        : {}),
+    };
+  });
+}
+
 type ExtractValues<T extends readonly SelectionItem[]> = T[number]["value"];
 
 export type SelectPromptOptions<
@@ -34,6 +66,10 @@ export type SelectPromptOptions<
   autocomplete?: boolean;
   defaultValue?: string;
   initialValue?: string;
+  backKey?: string; // e.g. "shift+tab" or "ctrl+b"; ends the prompt with a PromptBackError
+  timeout?: number; // seconds; resolves with the default value (or throws "Timeout") when it expires
+  resetTimeoutOnKey?: boolean;
+  other?: boolean | string; // appends an "Other…" entry (or one with this label); the typed text is returned as the value
 };
 
 export type MultiselectPromptOptions<
@@ -46,9 +82,13 @@ export type MultiselectPromptOptions<
   headerText?: string;
   footerText?: string;
   required?: boolean;
-  autocomplete?: boolean;
+  autocomplete?: boolean; // takes the letters, so the visual range mode is toggled with Ctrl+V instead of v
   defaultValue?: string[];
   initialValue?: string[]; // Array of values to pre-select (preferred over defaultValue if both specified)
+  backKey?: string; // e.g. "shift+tab" or "ctrl+b"; ends the prompt with a PromptBackError
+  timeout?: number; // seconds; resolves with the default value (or throws "Timeout") when it expires
+  resetTimeoutOnKey?: boolean;
+  other?: boolean | string; // appends an "Other…" entry (or one with this label); the typed text is added to the values
 };
 
 export type ConfirmPromptOptions = {
@@ -59,6 +99,9 @@ export type ConfirmPromptOptions = {
   required?: boolean;
   defaultValue?: boolean;
   initialValue?: boolean;
+  backKey?: string; // e.g. "shift+tab" or "ctrl+b"; ends the prompt with a PromptBackError
+  timeout?: number; // seconds; resolves with the default value (or throws "Timeout") when it expires
+  resetTimeoutOnKey?: boolean;
 };
 
 // Overload signatures for explicit type parameter support
@@ -83,48 +126,101 @@ export async function selectPrompt<
 >(
   options: SelectPromptOptions<TOptions>,
 ): Promise<ExtractValues<TOptions> | null> {
+  const answer = await runSelection(options);
+  if (!answer) {
+    return null;
+  }
+  if (answer.customValue !== undefined) {
+    return answer.customValue as ExtractValues<TOptions>;
+  }
+  // For a nested selection the value of the chosen item in its submenu
+  return answer.path[answer.path.length - 1] as ExtractValues<TOptions>;
+}
+
+// selectPathPrompt is selectPrompt for items with children: it resolves with the values
+// from the top-level item down to the chosen one, e.g. ["settings", "network"].
+export function selectPathPrompt(
+  options: SelectPromptOptions & { required: false },
+): Promise<string[] | null>;
+export function selectPathPrompt(
+  options: SelectPromptOptions & { required?: true },
+): Promise<string[]>;
+export async function selectPathPrompt(
+  options: SelectPromptOptions,
+): Promise<string[] | null> {
+  const answer = await runSelection(options);
+  if (!answer) {
+    return null;
+  }
+  if (answer.customValue !== undefined) {
+    return [answer.customValue];
+  }
+  return answer.path;
+}
+
+async function runSelection(
+  options: SelectPromptOptions<readonly SelectionItem[]>,
+): Promise<{ path: string[]; customValue?: string } | null> {
   const stringifiedItems = JSON.stringify(
-    options.options.map((item) => {
-      return {
-        value: item.value,
-        label: item.label,
-        hint: item.hint ?? "",
-        disabled: item.disabled ?? false,
-      };
-    }),
+    serializeSelectionItems(options.options),
   );
   const headerText =
     options.headerText ||
     formatPromptText(options.title, options.message) ||
     "Select an item: ";
-  const returnedPtr = symbols.CreateSelection(
-    ptr(encode(stringifiedItems)),
-    ptr(encode(headerText)),
-    ptr(encode(options.footerText || "")),
-    options.perPage || 5,
-    options.autocomplete ?? true,
-    ptr(encode(options.defaultValue || "")),
-    ptr(encode(options.initialValue || "")),
+  const returned = await awaitResult(
+    symbols.StartSelection(
+      ptr(encode(stringifiedItems)),
+      ptr(encode(headerText)),
+      ptr(encode(options.footerText || "")),
+      options.perPage || 5,
+      options.autocomplete ?? true,
+      ptr(encode(options.defaultValue || "")),
+      ptr(encode(options.initialValue || "")),
+      ptr(encode(options.backKey || "")),
+      options.timeout ?? 0,
+      options.resetTimeoutOnKey ?? false,
+      ptr(encode(otherLabel(options.other))),
+    ),
   );
-  const { selectedIndex, error } = JSON.parse(toString(returnedPtr)) as {
-    selectedIndex: string;
-    error: string;
-  };
+  const { selectedIndex, path, customValue, error, code, details, reason } =
+    JSON.parse(returned) as {
+      selectedIndex: string;
+      path?: string[];
+      customValue?: string;
+      error: string;
+      code?: PromptErrorCode;
+      details?: string;
+      reason?: string;
+    };
+  if (code === "BACK") {
+    const partial = path?.length
+      ? path[path.length - 1]
+      : selectedIndex !== ""
+        ? options.options[Number(selectedIndex)]?.value
+        : undefined;
+    throw new PromptBackError(partial ?? null);
+  }
   if (error !== "") {
-    if (error === "Cancelled") {
+    if (code === "CANCELLED") {
       if (options.required ?? true) {
-        cancel(error);
+        cancel(error, reason);
       }
       return null;
     }
-    throw new Error(error);
+    throw new PromptFailedError(code ?? "RENDER_FAILED", error, details);
+  }
+  if (customValue !== undefined) {
+    return { path: [], customValue };
   }
-  const index = Number(selectedIndex);
-  const selectedOption = options.options[index];
+  if (path?.length) {
+    return { path };
+  }
+  const selectedOption = options.options[Number(selectedIndex)];
   if (!selectedOption) {
     throw new Error("Invalid selection index");
   }
-  return selectedOption.value as ExtractValues<TOptions>;
+  return { path: [selectedOption.value] };
 }
 
 // Overload signatures for explicit type parameter support
@@ -160,6 +256,7 @@ export async function multiselectPrompt<
         label: item.label,
         hint: item.hint ?? "",
         disabled: item.disabled ?? false,
+        ...(item.kind ? { kind: item.kind } : {}),
       };
     }),
   );
@@ -173,27 +270,46 @@ export async function multiselectPrompt<
   // For cursor position, use first preselected value, or empty string
   const initialCursorValue =
     preselectedValues.length > 0 ? preselectedValues[0] : "";
-  const returnedPtr = symbols.CreateMultiselect(
-    ptr(encode(stringifiedItems)),
-    ptr(encode(headerText)),
-    ptr(encode(options.footerText || "")),
-    options.perPage || 5,
-    options.autocomplete ?? true,
-    ptr(encode(preselectedValuesJson)),
-    ptr(encode(initialCursorValue)),
+  const returned = await awaitResult(
+    symbols.StartMultiselect(
+      ptr(encode(stringifiedItems)),
+      ptr(encode(headerText)),
+      ptr(encode(options.footerText || "")),
+      options.perPage || 5,
+      options.autocomplete ?? true,
+      ptr(encode(preselectedValuesJson)),
+      ptr(encode(initialCursorValue)),
+      ptr(encode(options.backKey || "")),
+      options.timeout ?? 0,
+      options.resetTimeoutOnKey ?? false,
+      ptr(encode(otherLabel(options.other))),
+    ),
   );
-  const { selectedIndices, error } = JSON.parse(toString(returnedPtr)) as {
-    selectedIndices: string[];
-    error: string;
-  };
+  const { selectedIndices, customValue, error, code, details, reason } =
+    JSON.parse(returned) as {
+      selectedIndices: string[];
+      customValue?: string;
+      error: string;
+      code?: PromptErrorCode;
+      details?: string;
+      reason?: string;
+    };
+  if (code === "BACK") {
+    const partial = selectedIndices
+      .map((idx) => options.options[Number(idx)]?.value)
+      .filter((value) => value !== undefined);
+    throw new PromptBackError(
+      customValue !== undefined ? [...partial, customValue] : partial,
+    );
+  }
   if (error !== "") {
-    if (error === "Cancelled") {
+    if (code === "CANCELLED") {
       if (options.required ?? true) {
-        cancel(error);
+        cancel(error, reason);
       }
       return null;
     }
-    throw new Error(error);
+    throw new PromptFailedError(code ?? "RENDER_FAILED", error, details);
   }
   const indices = selectedIndices.map((idx) => Number(idx));
   const values = indices
@@ -202,6 +318,9 @@ export async function multiselectPrompt<
   if (values.length !== indices.length) {
     throw new Error("Invalid selection indices");
   }
+  if (customValue !== undefined) {
+    values.push(customValue as ExtractValues<TOptions>);
+  }
   return values;
 }
 
@@ -213,26 +332,37 @@ export async function confirmPrompt(
     options.defaultValue !== undefined ? String(options.defaultValue) : "";
   const initialValue =
     options.initialValue !== undefined ? String(options.initialValue) : "";
-  const returnedPtr = symbols.CreateConfirm(
-    ptr(encode(promptText)),
-    ptr(encode(options.headerText || "")),
-    ptr(encode(options.footerText || "")),
-    ptr(encode(defaultValue)),
-    ptr(encode(initialValue)),
+  const returned = await awaitResult(
+    symbols.StartConfirm(
+      ptr(encode(promptText)),
+      ptr(encode(options.headerText || "")),
+      ptr(encode(options.footerText || "")),
+      ptr(encode(defaultValue)),
+      ptr(encode(initialValue)),
+      ptr(encode(options.backKey || "")),
+      options.timeout ?? 0,
+      options.resetTimeoutOnKey ?? false,
+    ),
   );
-  const { confirmed, error } = JSON.parse(toString(returnedPtr)) as {
+  const { confirmed, error, code, details, reason } = JSON.parse(returned) as {
     confirmed: string;
     error: string;
+    code?: PromptErrorCode;
+    details?: string;
+    reason?: string;
   };
+  if (code === "BACK") {
+    throw new PromptBackError(confirmed === "true");
+  }
   if (error !== "") {
-    if (error === "Cancelled") {
+    if (code === "CANCELLED") {
       if (options.required ?? true) {
-        cancel(error);
+        cancel(error, reason);
       }
       // If not required, return defaultValue or false
       return options.defaultValue ?? false;
     }
-    throw new Error(error);
+    throw new PromptFailedError(code ?? "RENDER_FAILED", error, details);
   }
   if (confirmed === "") {
     throw new Error("No confirmation received");
@@ -253,11 +383,14 @@ export type GroupMultiselectPromptOptions<
   headerText?: string;
   footerText?: string;
   required?: boolean;
-  autocomplete?: boolean;
+  autocomplete?: boolean; // takes the letters, so the visual range mode is toggled with Ctrl+V instead of v
   defaultValue?: string[];
   initialValue?: string[]; // Array of values to pre-select (preferred over defaultValue if both specified)
   selectableGroups?: boolean;
   groupSpacing?: number;
+  backKey?: string; // e.g. "shift+tab" or "ctrl+b"; ends the prompt with a PromptBackError
+  timeout?: number; // seconds; resolves with the default value (or throws "Timeout") when it expires
+  resetTimeoutOnKey?: boolean;
 };
 
 type GroupedSelectionItem = SelectionItem & {
@@ -265,6 +398,43 @@ type GroupedSelectionItem = SelectionItem & {
   groupName: string;
 };
 
+// flattenGroups lists each group header followed by the items of its group, in the
+// order the native grouped prompts expect.
+function flattenGroups(
+  groups: Record<string, readonly SelectionItem[]>,
+): GroupedSelectionItem[] {
+  const flattenedItems: GroupedSelectionItem[] = [];
+  for (const [groupName, groupItems] of Object.entries(groups)) {
+    flattenedItems.push({
+      value: `__group__${groupName}`,
+      label: groupName,
+      hint: "",
+      disabled: false,
+      isGroupHeader: true,
+      groupName,
+    });
+    for (const item of groupItems) {
+      flattenedItems.push({ ...item, isGroupHeader: false, groupName });
+    }
+  }
+  return flattenedItems;
+}
+
+function serializeGroupedItems(items: GroupedSelectionItem[]): string {
+  return JSON.stringify(
+    items.map((item) => {
+      return {
+        value: item.value,
+        label: item.label,
+        hint: item.hint ?? "",
+        disabled: item.disabled ?? false,
+        isGroupHeader: item.isGroupHeader,
+        groupName: item.groupName,
+      };
+    }),
+  );
+}
+
 // Overload signatures for explicit type parameter support
 export function groupMultiselectPrompt<T extends string>(
   options: GroupMultiselectPromptOptions<
@@ -296,44 +466,8 @@ export async function groupMultiselectPrompt<
   options: GroupMultiselectPromptOptions<TOptions>,
 ): Promise<ExtractValues<TOptions[keyof TOptions]>[] | null> {
   // Flatten grouped options into a list with group headers
-  const flattenedItems: GroupedSelectionItem[] = [];
-  const valueToIndexMap = new Map<string, number>();
-
-  for (const [groupName, groupItems] of Object.entries(options.options)) {
-    // Add group header
-    flattenedItems.push({
-      value: `__group__${groupName}`,
-      label: groupName,
-      hint: "",
-      disabled: false,
-      isGroupHeader: true,
-      groupName,
-    });
-
-    // Add items in the group
-    for (const item of groupItems) {
-      const index = flattenedItems.length;
-      valueToIndexMap.set(item.value, index);
-      flattenedItems.push({
-        ...item,
-        isGroupHeader: false,
-        groupName,
-      });
-    }
-  }
-
-  const stringifiedItems = JSON.stringify(
-    flattenedItems.map((item) => {
-      return {
-        value: item.value,
-        label: item.label,
-        hint: item.hint ?? "",
-        disabled: item.disabled ?? false,
-        isGroupHeader: item.isGroupHeader,
-        groupName: item.groupName,
-      };
-    }),
-  );
+  const flattenedItems = flattenGroups(options.options);
+  const stringifiedItems = serializeGroupedItems(flattenedItems);
 
   const headerText =
     options.headerText ||
@@ -347,29 +481,45 @@ export async function groupMultiselectPrompt<
   const initialCursorValue =
     preselectedValues.length > 0 ? preselectedValues[0] : "";
 
-  const returnedPtr = symbols.CreateGroupMultiselect(
-    ptr(encode(stringifiedItems)),
-    ptr(encode(headerText)),
-    ptr(encode(options.footerText || "")),
-    options.perPage || 5,
-    options.autocomplete ?? true,
-    options.selectableGroups ?? false,
-    ptr(encode(preselectedValuesJson)),
-    ptr(encode(initialCursorValue)),
-    options.groupSpacing ?? 0,
+  const returned = await awaitResult(
+    symbols.StartGroupMultiselect(
+      ptr(encode(stringifiedItems)),
+      ptr(encode(headerText)),
+      ptr(encode(options.footerText || "")),
+      options.perPage || 5,
+      options.autocomplete ?? true,
+      options.selectableGroups ?? false,
+      ptr(encode(preselectedValuesJson)),
+      ptr(encode(initialCursorValue)),
+      options.groupSpacing ?? 0,
+      ptr(encode(options.backKey || "")),
+      options.timeout ?? 0,
+      options.resetTimeoutOnKey ?? false,
+    ),
   );
-  const { selectedIndices, error } = JSON.parse(toString(returnedPtr)) as {
+  const { selectedIndices, error, code, details, reason } = JSON.parse(returned) as {
     selectedIndices: string[];
     error: string;
+    code?: PromptErrorCode;
+    details?: string;
+    reason?: string;
   };
+  if (code === "BACK") {
+    throw new PromptBackError(
+      selectedIndices
+        .map((idx) => flattenedItems[Number(idx)])
+        .filter((item) => item && !item.isGroupHeader)
+        .map((item) => item?.value),
+    );
+  }
   if (error !== "") {
-    if (error === "Cancelled") {
+    if (code === "CANCELLED") {
       if (options.required ?? true) {
-        cancel(error);
+        cancel(error, reason);
       }
       return null;
     }
-    throw new Error(error);
+    throw new PromptFailedError(code ?? "RENDER_FAILED", error, details);
   }
   const indices = selectedIndices.map((idx) => Number(idx));
   const values = indices
@@ -386,3 +536,109 @@ export async function groupMultiselectPrompt<
   }
   return values;
 }
+
+export type GroupSelectPromptOptions<
+  TOptions extends Record<string, readonly SelectionItem[]> = Record<
+    string,
+    readonly SelectionItem[]
+  >,
+> = {
+  message: string;
+  title?: string;
+  options: TOptions;
+  perPage?: number;
+  headerText?: string;
+  footerText?: string;
+  required?: boolean;
+  autocomplete?: boolean;
+  defaultValue?: string; // chosen on Enter without moving the cursor, and on timeout
+  initialValue?: string;
+  groupSpacing?: number;
+  backKey?: string; // e.g. "shift+tab" or "ctrl+b"; ends the prompt with a PromptBackError
+  timeout?: number; // seconds; resolves with the default value (or throws "Timeout") when it expires
+  resetTimeoutOnKey?: boolean;
+};
+
+// Overload signatures for explicit type parameter support
+export function groupSelectPrompt<T extends string>(
+  options: GroupSelectPromptOptions<
+    Record<string, readonly SelectionItem<T>[]>
+  > & {
+    required: false;
+  },
+): Promise<T | null>;
+export function groupSelectPrompt<T extends string>(
+  options: GroupSelectPromptOptions<
+    Record<string, readonly SelectionItem<T>[]>
+  > & {
+    required?: true;
+  },
+): Promise<T>;
+export function groupSelectPrompt<
+  const TOptions extends Record<string, readonly SelectionItem[]>,
+>(
+  options: GroupSelectPromptOptions<TOptions> & { required: false },
+): Promise<ExtractValues<TOptions[keyof TOptions]> | null>;
+export function groupSelectPrompt<
+  const TOptions extends Record<string, readonly SelectionItem[]>,
+>(
+  options: GroupSelectPromptOptions<TOptions> & { required?: true },
+): Promise<ExtractValues<TOptions[keyof TOptions]>>;
+export async function groupSelectPrompt<
+  const TOptions extends Record<string, readonly SelectionItem[]>,
+>(
+  options: GroupSelectPromptOptions<TOptions>,
+): Promise<ExtractValues<TOptions[keyof TOptions]> | null> {
+  const flattenedItems = flattenGroups(options.options);
+  const headerText =
+    options.headerText ||
+    formatPromptText(options.title, options.message) ||
+    "Select an item: ";
+
+  const returned = await awaitResult(
+    symbols.StartGroupSelect(
+      ptr(encode(serializeGroupedItems(flattenedItems))),
+      ptr(encode(headerText)),
+      ptr(encode(options.footerText || "")),
+      options.perPage || 10,
+      options.autocomplete ?? true,
+      ptr(encode(options.defaultValue || "")),
+      ptr(encode(options.initialValue || "")),
+      options.groupSpacing ?? 0,
+      ptr(encode(options.backKey || "")),
+      options.timeout ?? 0,
+      options.resetTimeoutOnKey ?? false,
+    ),
+  );
+  const { selectedIndex, error, code, details, reason } = JSON.parse(
+    returned,
+  ) as {
+    selectedIndex: string;
+    error: string;
+    code?: PromptErrorCode;
+    details?: string;
+    reason?: string;
+  };
+  const selected =
+    selectedIndex !== "" ? flattenedItems[Number(selectedIndex)] : undefined;
+  const value =
+    selected && !selected.isGroupHeader
+      ? (selected.value as ExtractValues<TOptions[keyof TOptions]>)
+      : undefined;
+  if (code === "BACK") {
+    throw new PromptBackError(value ?? null);
+  }
+  if (error !== "") {
+    if (code === "CANCELLED") {
+      if (options.required ?? true) {
+        cancel(error, reason);
+      }
+      return null;
+    }
+    throw new PromptFailedError(code ?? "RENDER_FAILED", error, details);
+  }
+  if (value === undefined) {
+    throw new Error("Invalid selection index");
+  }
+  return value;
+}
@@ -1,17 +1,22 @@
+import { ptr } from "bun:ffi";
+import { symbols } from "./ffi";
+import { encode } from "./utils";
+
 export type SpinnerIndicator = "timer" | "dots";
 
 export interface SpinnerOptions {
   text?: string;
-  indicator?: SpinnerIndicator;
+  indicator?: SpinnerIndicator; // "timer" (default) shows the elapsed time, "dots" only the frames
   frames?: string[];
-  delay?: number;
+  delay?: number; // ms per frame; the renderer redraws every 80ms, so shorter delays skip frames
   onCancel?: () => void;
   cancelMessage?: string;
   errorMessage?: string;
   successText?: string;
   failText?: string;
   prefixText?: string;
-  color?: string;
+  color?: string; // ANSI color number (e.g. "6") or hex color (e.g. "#00afff")
+  /** @deprecated The native renderer always hides the cursor while it draws; `false` throws */
   hideCursor?: boolean;
   silent?: boolean;
   signal?: AbortSignal;
@@ -25,160 +30,110 @@ export interface SpinnerInstance {
   updateText(text: string): SpinnerInstance;
 }
 
-const DEFAULT_FRAMES = ["◒", "◐", "◓", "◑"];
-const DEFAULT_DELAY = 80;
-
-function hideCursor(): void {
-  process.stdout.write("\u001B[?25l");
-}
-
-function showCursor(): void {
-  process.stdout.write("\u001B[?25h");
-}
-
-function clearLine(): void {
-  process.stdout.write("\r\u001B[K");
-}
-
-function formatTime(seconds: number): string {
-  if (seconds < 60) {
-    return `${seconds}s`;
-  }
-  const mins = Math.floor(seconds / 60);
-  const secs = seconds % 60;
-  return `${mins}m ${secs}s`;
-}
-
 export function spinnerPrompt(options: SpinnerOptions = {}): SpinnerInstance {
   return createSpinner(options);
 }
 
+/**
+ * Creates a spinner drawn by the native live renderer. Spinners and progress bars share
+ * one renderer, and they pause while a prompt is open instead of drawing over it.
+ */
 export function createSpinner(options: SpinnerOptions = {}): SpinnerInstance {
   const {
     text = "",
-    indicator = "dots",
-    frames = DEFAULT_FRAMES,
-    delay = DEFAULT_DELAY,
+    indicator = "timer",
+    frames,
+    delay = 0,
+    color = "",
+    hideCursor = true,
     onCancel,
     cancelMessage = "Operation cancelled by user",
     errorMessage = "Operation failed",
     successText,
     failText,
     prefixText,
-    hideCursor: shouldHideCursor = true,
     silent = false,
     signal,
   } = options;
 
-  let frameIndex = 0;
-  let intervalId: ReturnType<typeof setInterval> | null = null;
-  let startTime: number | null = null;
-  let isRunning = false;
-  let currentText = text;
-
-  const render = (): void => {
-    if (silent || !isRunning) {
-      return;
-    }
-
-    clearLine();
+  if (!hideCursor) {
+    throw new Error(
+      "createSpinner: `hideCursor: false` is no longer supported, the native renderer always hides the cursor while it draws",
+    );
+  }
+  const framesJSON = frames?.length ? JSON.stringify(frames) : "";
 
-    const frame = frames[frameIndex % frames.length];
-    let output = `${frame}  ${currentText}`;
+  // The handle of the native spinner while it is running
+  let handle: number | null = null;
+  let currentText = text;
 
-    if (indicator === "timer" && startTime !== null) {
-      const elapsed = Math.floor((Date.now() - startTime) / 1000);
-      output += ` [${formatTime(elapsed)}]`;
-    }
+  const label = (value: string): Uint8Array =>
+    encode(prefixText ? `${prefixText}${value}` : value);
+
+  const begin = (): number => {
+    handle ??= symbols.SpinnerStartStyled(
+      ptr(label(currentText)),
+      ptr(encode(framesJSON)),
+      Math.max(0, Math.trunc(delay)),
+      ptr(encode(color)),
+      indicator === "dots",
+    );
+    return handle as number;
+  };
 
-    if (prefixText) {
-      output = `${prefixText}${output}`;
+  const end = (
+    finish: (id: number, text: any) => boolean,
+    displayText: string,
+  ): void => {
+    if (silent) {
+      handle = null;
+      return;
     }
-
-    process.stdout.write(output);
-    frameIndex += 1;
+    finish(begin(), ptr(label(displayText)));
+    handle = null;
   };
 
   const start = (): SpinnerInstance => {
-    if (isRunning) {
+    if (handle !== null || silent) {
       return spinner;
     }
-
-    isRunning = true;
-    startTime = Date.now();
-
-    if (shouldHideCursor && !silent) {
-      hideCursor();
-    }
-
-    if (signal) {
-      signal.addEventListener("abort", () => {
-        stop();
-        if (onCancel) {
-          onCancel();
-        }
-        if (!silent) {
-          clearLine();
-          process.stdout.write(`✗  ${cancelMessage}\n`);
+    begin();
+    signal?.addEventListener(
+      "abort",
+      () => {
+        if (handle === null) {
+          return;
         }
-      });
-    }
-
-    if (!silent) {
-      render();
-      intervalId = setInterval(() => {
-        render();
-      }, delay);
-    }
-
+        end(symbols.SpinnerFail, cancelMessage);
+        onCancel?.();
+      },
+      { once: true },
+    );
     return spinner;
   };
 
   const stop = (): SpinnerInstance => {
-    if (!isRunning) {
-      return spinner;
+    if (handle !== null) {
+      symbols.SpinnerStop(handle);
+      handle = null;
     }
-
-    isRunning = false;
-
-    if (intervalId !== null) {
-      clearInterval(intervalId);
-      intervalId = null;
-    }
-
-    if (shouldHideCursor && !silent) {
-      showCursor();
-    }
-
-    if (!silent) {
-      clearLine();
-    }
-
     return spinner;
   };
 
   const succeed = (customText?: string): SpinnerInstance => {
-    stop();
-    if (!silent) {
-      const displayText = customText ?? successText ?? currentText;
-      process.stdout.write(`✓  ${displayText}\n`);
-    }
+    end(symbols.SpinnerSucceed, customText ?? successText ?? currentText);
     return spinner;
   };
 
   const fail = (customText?: string): SpinnerInstance => {
-    stop();
-    if (!silent) {
-      const displayText = customText ?? failText ?? errorMessage;
-      process.stdout.write(`✗  ${displayText}\n`);
-    }
+    end(symbols.SpinnerFail, customText ?? failText ?? errorMessage);
     return spinner;
   };
 
   const updateText = (newText: string): SpinnerInstance => {
     currentText = newText;
-    if (isRunning && !silent) {
-      render();
+    if (handle !== null) {
+      symbols.SpinnerUpdate(handle, ptr(label(currentText)));
     }
     return spinner;
   };
@@ -0,0 +1,111 @@
+import { ptr } from "bun:ffi";
+import {
+  cancel,
+  PromptBackError,
+  type PromptErrorCode,
+  PromptFailedError,
+} from "./cancel";
+import { symbols } from "./ffi";
+import { awaitResult, encode } from "./utils";
+
+export type StreamItem<T extends string = string> = {
+  value: T;
+  label: string;
+  hint?: string;
+  disabled?: boolean;
+};
+
+export type StreamSelectPromptOptions<T extends string = string> = {
+  message: string;
+  options?: readonly StreamItem<T>[]; // items known when the prompt opens
+  perPage?: number;
+  footerText?: string;
+  required?: boolean;
+  defaultValue?: T; // chosen on Enter without moving the cursor, once it has arrived
+  initialValue?: T; // the cursor moves to it as soon as it arrives
+  backKey?: string; // e.g. "shift+tab" or "ctrl+b"; ends the prompt with a PromptBackError
+  timeout?: number; // seconds; resolves with the default value (or throws "Timeout") when it expires
+  resetTimeoutOnKey?: boolean;
+};
+
+export interface StreamSelectInstance<T extends string = string> {
+  // Each change returns false once the prompt is closed
+  append(items: readonly StreamItem<T>[]): boolean;
+  update(item: StreamItem<T>): boolean;
+  remove(value: T): boolean;
+  // Hides the loading indicator once every item has arrived
+  complete(): boolean;
+  cancel(): boolean;
+  result: Promise<T | null>;
+}
+
+function serializeStreamItem(item: StreamItem): Record<string, unknown> {
+  return {
+    value: item.value,
+    label: item.label,
+    hint: item.hint ?? "",
+    disabled: item.disabled ?? false,
+  };
+}
+
+/**
+ * Opens a single-choice list whose items keep arriving while it is open, e.g. search
+ * results. The items are changed through the returned instance; `result` resolves with
+ * the chosen value.
+ */
+export function streamSelectPrompt<T extends string>(
+  options: StreamSelectPromptOptions<T>,
+): StreamSelectInstance<T> {
+  const items = (options.options ?? []).map(serializeStreamItem);
+  const handle: number = symbols.StartStreamSelection(
+    ptr(encode(JSON.stringify(items))),
+    ptr(encode(options.message)),
+    ptr(encode(options.footerText || "")),
+    options.perPage || 5,
+    ptr(encode(options.defaultValue || "")),
+    ptr(encode(options.initialValue || "")),
+    ptr(encode(options.backKey || "")),
+    options.timeout ?? 0,
+    options.resetTimeoutOnKey ?? false,
+  );
+
+  const result = awaitResult(handle).then((returned) => {
+    const { value, error, code, details, reason } = JSON.parse(returned) as {
+      value: string;
+      error: string;
+      code?: PromptErrorCode;
+      details?: string;
+      reason?: string;
+    };
+    if (code === "BACK") {
+      throw new PromptBackError(value || null);
+    }
+    if (error !== "") {
+      if (code === "CANCELLED") {
+        if (options.required ?? true) {
+          cancel(error, reason);
+        }
+        return null;
+      }
+      throw new PromptFailedError(code ?? "RENDER_FAILED", error, details);
+    }
+    return value as T;
+  });
+
+  return {
+    append: (items) =>
+      symbols.StreamAppend(
+        handle,
+        ptr(encode(JSON.stringify(items.map(serializeStreamItem)))),
+      ),
+    update: (item) =>
+      symbols.StreamUpdate(
+        handle,
+        ptr(encode(JSON.stringify(serializeStreamItem(item)))),
+      ),
+    remove: (value) => symbols.StreamRemove(handle, ptr(encode(value))),
+    complete: () => symbols.StreamComplete(handle),
+    cancel: () => symbols.CancelPrompt(handle),
+    result,
+  };
+}
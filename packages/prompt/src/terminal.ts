@@ -0,0 +1,16 @@
+import { ptr } from "bun:ffi";
+import { symbols } from "./ffi";
+import { encode, toString } from "./utils";
+
+/**
+ * Sets the terminal prompts read keys from and render to
+ * (also configurable with the `DLER_PROMPT_TTY` environment variable)
+ * @param path - A terminal device such as `/dev/tty`, `"stdio"` for stdin/stdout,
+ * or `""` for the default. A prompt that is already open keeps its terminal.
+ */
+export function setPromptTerminal(path: string): void {
+  const error = toString(symbols.SetTerminalPath(ptr(encode(path))));
+  if (error !== "") {
+    throw new Error(error);
+  }
+}
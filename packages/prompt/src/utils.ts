@@ -12,3 +12,16 @@ export function toString(ptr: any): string {
   symbols.FreeString(str.ptr);
   return str.toString();
 }
+
+const POLL_INTERVAL = 16;
+
+// Waits for a prompt started with one of the Start* symbols without blocking the event loop.
+export async function awaitResult(handle: number): Promise<string> {
+  while (true) {
+    const result = toString(symbols.PollResult(handle));
+    if (result !== "") {
+      return result;
+    }
+    await Bun.sleep(POLL_INTERVAL);
+  }
+}